@@ -0,0 +1,224 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command snapshot-validator checks VolumeSnapshot/VolumeSnapshotContent/
+// VolumeSnapshotClass objects against the rules in pkg/validation. It runs either as
+// a ValidatingAdmissionWebhook server (--webhook) or as an offline linter over a YAML
+// file (--lint), emitting machine-readable JSON diagnostics suitable for CI gating.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/golang/glog"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/validation"
+)
+
+var (
+	webhookAddr = flag.String("webhook-address", "", "if set, run as a ValidatingAdmissionWebhook HTTPS server listening on this address")
+	tlsCertFile = flag.String("tls-cert-file", "", "TLS certificate file for --webhook-address")
+	tlsKeyFile  = flag.String("tls-private-key-file", "", "TLS private key file for --webhook-address")
+	lintFile    = flag.String("lint", "", "path to a YAML file to lint offline instead of running as a webhook")
+	kubeconfig  = flag.String("kubeconfig", "", "path to a kubeconfig; if unset and --webhook-address is set, the in-cluster config is used")
+)
+
+// volumeSnapshotContentResource is the GroupVersionResource of VolumeSnapshotContent,
+// used to check rule (5) via the dynamic client since this repo has no generated
+// typed clientset for its own CRDs.
+var volumeSnapshotContentResource = schema.GroupVersionResource{
+	Group:    "snapshot.storage.k8s.io",
+	Version:  "v1beta1",
+	Resource: v1beta1.VolumeSnapshotContentResourcePlural,
+}
+
+// validator bundles the cluster access the webhook needs to enforce rules (3) and
+// (5), which the offline linter has no way to check. Both fields are nil in that
+// offline mode, and the corresponding rules are skipped.
+type validator struct {
+	knownDrivers  map[string]bool
+	dynamicClient dynamic.Interface
+}
+
+func main() {
+	flag.Parse()
+
+	if *lintFile != "" {
+		os.Exit(runLint(*lintFile))
+	}
+
+	if *webhookAddr == "" {
+		glog.Fatal("one of --webhook-address or --lint must be set")
+	}
+
+	v, err := newValidator(*kubeconfig)
+	if err != nil {
+		glog.Fatalf("failed to set up cluster access for the webhook: %v", err)
+	}
+	runWebhook(v, *webhookAddr, *tlsCertFile, *tlsKeyFile)
+}
+
+func newValidator(kubeconfigPath string) (*validator, error) {
+	var config *rest.Config
+	var err error
+	if kubeconfigPath != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	} else {
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	drivers, err := clientset.StorageV1beta1().CSIDrivers().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	knownDrivers := make(map[string]bool, len(drivers.Items))
+	for _, d := range drivers.Items {
+		knownDrivers[d.Name] = true
+	}
+
+	return &validator{knownDrivers: knownDrivers, dynamicClient: dynamicClient}, nil
+}
+
+// contentExists checks rule (5): whether a VolumeSnapshotContent a VolumeSnapshot
+// claims to be bound to still exists.
+func (v *validator) contentExists(name string) (bool, error) {
+	_, err := v.dynamicClient.Resource(volumeSnapshotContentResource).Get(name, metav1.GetOptions{})
+	if err == nil {
+		return true, nil
+	}
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func runLint(path string) int {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		glog.Fatalf("failed to read %s: %v", path, err)
+	}
+
+	diags, err := validation.LintYAML(data)
+	if err != nil {
+		glog.Fatalf("failed to lint %s: %v", path, err)
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(diags); err != nil {
+		glog.Fatalf("failed to encode diagnostics: %v", err)
+	}
+	if len(diags) > 0 {
+		return 1
+	}
+	return 0
+}
+
+func runWebhook(v *validator, addr, certFile, keyFile string) {
+	http.HandleFunc("/validate", v.serveAdmissionReview)
+	glog.Infof("snapshot-validator listening on %s", addr)
+	if err := http.ListenAndServeTLS(addr, certFile, keyFile, nil); err != nil {
+		glog.Fatalf("webhook server failed: %v", err)
+	}
+}
+
+func (v *validator) serveAdmissionReview(w http.ResponseWriter, r *http.Request) {
+	review := &admissionv1beta1.AdmissionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review.Response = v.admit(review.Request)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+func (v *validator) admit(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	var diags []validation.Diagnostic
+
+	switch req.Kind.Kind {
+	case "VolumeSnapshot":
+		snap := &v1beta1.VolumeSnapshot{}
+		if err := json.Unmarshal(req.Object.Raw, snap); err != nil {
+			return deny(req.UID, err.Error())
+		}
+
+		if req.Operation == admissionv1beta1.Update {
+			old := &v1beta1.VolumeSnapshot{}
+			if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+				return deny(req.UID, err.Error())
+			}
+			diags = validation.ValidateVolumeSnapshotUpdate(old, snap)
+		} else {
+			diags = validation.ValidateVolumeSnapshot(snap)
+		}
+		diags = append(diags, validation.ValidateVolumeSnapshotBinding(snap, v.contentExists)...)
+	case "VolumeSnapshotContent":
+		content := &v1beta1.VolumeSnapshotContent{}
+		if err := json.Unmarshal(req.Object.Raw, content); err != nil {
+			return deny(req.UID, err.Error())
+		}
+		diags = validation.ValidateVolumeSnapshotContent(content)
+	case "VolumeSnapshotClass":
+		class := &v1beta1.VolumeSnapshotClass{}
+		if err := json.Unmarshal(req.Object.Raw, class); err != nil {
+			return deny(req.UID, err.Error())
+		}
+		diags = validation.ValidateVolumeSnapshotClass(class, v.knownDrivers)
+	default:
+		return &admissionv1beta1.AdmissionResponse{UID: req.UID, Allowed: true}
+	}
+
+	if len(diags) > 0 {
+		return deny(req.UID, diags[0].Message)
+	}
+	return &admissionv1beta1.AdmissionResponse{UID: req.UID, Allowed: true}
+}
+
+func deny(uid types.UID, message string) *admissionv1beta1.AdmissionResponse {
+	return &admissionv1beta1.AdmissionResponse{
+		UID:     uid,
+		Allowed: false,
+		Result: &metav1.Status{
+			Message: message,
+		},
+	}
+}
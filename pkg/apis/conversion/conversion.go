@@ -0,0 +1,217 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conversion round-trips VolumeSnapshot, VolumeSnapshotContent and
+// VolumeSnapshotClass API objects between v1alpha1 and v1beta1 so that existing
+// v1alpha1 objects keep working while the API graduates.
+package conversion
+
+import (
+	"fmt"
+
+	storage "k8s.io/api/storage/v1beta1"
+
+	v1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+)
+
+// ConvertVolumeSnapshotToV1beta1 converts a v1alpha1 VolumeSnapshot to v1beta1.
+// v1alpha1 has no immutable Source union, so Source.PersistentVolumeClaimName/
+// VolumeSnapshotContentName are derived from whichever of Spec.Source/
+// Spec.SnapshotContentName was set.
+func ConvertVolumeSnapshotToV1beta1(in *v1alpha1.VolumeSnapshot) (*v1beta1.VolumeSnapshot, error) {
+	out := &v1beta1.VolumeSnapshot{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.TypeMeta.APIVersion = "snapshot.storage.k8s.io/v1beta1"
+
+	switch {
+	case in.Spec.SnapshotContentName != "":
+		out.Spec.Source.VolumeSnapshotContentName = strPtr(in.Spec.SnapshotContentName)
+	case in.Spec.Source != nil:
+		out.Spec.Source.PersistentVolumeClaimName = strPtr(in.Spec.Source.Name)
+	default:
+		return nil, fmt.Errorf("VolumeSnapshot %s/%s has neither Spec.Source nor Spec.SnapshotContentName set", in.Namespace, in.Name)
+	}
+	if in.Spec.VolumeSnapshotClassName != "" {
+		out.Spec.VolumeSnapshotClassName = strPtr(in.Spec.VolumeSnapshotClassName)
+	}
+
+	out.Status = &v1beta1.VolumeSnapshotStatus{
+		CreationTime: in.Status.CreationTime,
+		ReadyToUse:   boolPtr(in.Status.Ready),
+	}
+	if in.Status.Error != nil {
+		out.Status.Error = &v1beta1.VolumeSnapshotError{Message: strPtr(in.Status.Error.Message)}
+	}
+	return out, nil
+}
+
+// ConvertVolumeSnapshotFromV1beta1 converts a v1beta1 VolumeSnapshot back to
+// v1alpha1.
+func ConvertVolumeSnapshotFromV1beta1(in *v1beta1.VolumeSnapshot) (*v1alpha1.VolumeSnapshot, error) {
+	out := &v1alpha1.VolumeSnapshot{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.TypeMeta.APIVersion = "snapshot.storage.k8s.io/v1alpha1"
+
+	switch {
+	case in.Spec.Source.VolumeSnapshotContentName != nil:
+		out.Spec.SnapshotContentName = *in.Spec.Source.VolumeSnapshotContentName
+	case in.Spec.Source.PersistentVolumeClaimName != nil:
+		out.Spec.Source = &v1alpha1.TypedLocalObjectReference{
+			Name: *in.Spec.Source.PersistentVolumeClaimName,
+			Kind: "PersistentVolumeClaim",
+		}
+	default:
+		return nil, fmt.Errorf("VolumeSnapshot %s/%s has neither Source.PersistentVolumeClaimName nor Source.VolumeSnapshotContentName set", in.Namespace, in.Name)
+	}
+	if in.Spec.VolumeSnapshotClassName != nil {
+		out.Spec.VolumeSnapshotClassName = *in.Spec.VolumeSnapshotClassName
+	}
+
+	if in.Status != nil {
+		out.Status.CreationTime = in.Status.CreationTime
+		if in.Status.ReadyToUse != nil {
+			out.Status.Ready = *in.Status.ReadyToUse
+		}
+		if in.Status.Error != nil {
+			out.Status.Error = &storage.VolumeError{}
+			if in.Status.Error.Message != nil {
+				out.Status.Error.Message = *in.Status.Error.Message
+			}
+		}
+	}
+	return out, nil
+}
+
+// ConvertVolumeSnapshotContentToV1beta1 converts a v1alpha1 VolumeSnapshotContent to
+// v1beta1. v1alpha1 only ever describes a content as a CSI snapshot handle (there is
+// no raw-VolumeHandle source at this API version), so Spec.Source.SnapshotHandle is
+// always populated and Spec.Source.VolumeHandle is always left nil. The CreatedAt/Size
+// fields v1alpha1 embeds inline on CSIVolumeSnapshotSource become the v1beta1 Status
+// subresource instead.
+func ConvertVolumeSnapshotContentToV1beta1(in *v1alpha1.VolumeSnapshotContent) (*v1beta1.VolumeSnapshotContent, error) {
+	if in.Spec.CSI == nil {
+		return nil, fmt.Errorf("VolumeSnapshotContent %s has no CSI source set", in.Name)
+	}
+
+	out := &v1beta1.VolumeSnapshotContent{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.TypeMeta.APIVersion = "snapshot.storage.k8s.io/v1beta1"
+
+	out.Spec.Driver = in.Spec.CSI.Driver
+	out.Spec.Source.SnapshotHandle = strPtr(in.Spec.CSI.SnapshotHandle)
+	if in.Spec.VolumeSnapshotRef != nil {
+		out.Spec.VolumeSnapshotRef = *in.Spec.VolumeSnapshotRef
+	}
+	if in.Spec.VolumeSnapshotClassName != "" {
+		out.Spec.VolumeSnapshotClassName = strPtr(in.Spec.VolumeSnapshotClassName)
+	}
+	out.Spec.DeletionPolicy = in.Spec.DeletionPolicy
+	if out.Spec.DeletionPolicy == "" {
+		out.Spec.DeletionPolicy = v1beta1.VolumeSnapshotContentDelete
+	}
+
+	out.Status = &v1beta1.VolumeSnapshotContentStatus{
+		SnapshotHandle: strPtr(in.Spec.CSI.SnapshotHandle),
+	}
+	if in.Spec.CSI.CreatedAt != 0 {
+		out.Status.CreationTime = int64Ptr(in.Spec.CSI.CreatedAt)
+	}
+	if in.Spec.CSI.Size != 0 {
+		out.Status.RestoreSize = int64Ptr(in.Spec.CSI.Size)
+	}
+	return out, nil
+}
+
+// ConvertVolumeSnapshotContentFromV1beta1 converts a v1beta1 VolumeSnapshotContent
+// back to v1alpha1. A Content sourced from a raw Spec.Source.VolumeHandle (dynamic
+// provisioning that hasn't cut a snapshot yet) has no v1alpha1 equivalent and is
+// rejected rather than silently dropped; only a Content that already carries a
+// SnapshotHandle can round-trip.
+func ConvertVolumeSnapshotContentFromV1beta1(in *v1beta1.VolumeSnapshotContent) (*v1alpha1.VolumeSnapshotContent, error) {
+	if in.Spec.Source.SnapshotHandle == nil {
+		return nil, fmt.Errorf("VolumeSnapshotContent %s has no Source.SnapshotHandle set; v1alpha1 cannot represent a content sourced from a raw VolumeHandle", in.Name)
+	}
+
+	out := &v1alpha1.VolumeSnapshotContent{
+		TypeMeta:   in.TypeMeta,
+		ObjectMeta: in.ObjectMeta,
+	}
+	out.TypeMeta.APIVersion = "snapshot.storage.k8s.io/v1alpha1"
+
+	csi := &v1alpha1.CSIVolumeSnapshotSource{
+		Driver:         in.Spec.Driver,
+		SnapshotHandle: *in.Spec.Source.SnapshotHandle,
+	}
+	if in.Status != nil {
+		if in.Status.CreationTime != nil {
+			csi.CreatedAt = *in.Status.CreationTime
+		}
+		if in.Status.RestoreSize != nil {
+			csi.Size = *in.Status.RestoreSize
+		}
+	}
+	out.Spec.CSI = csi
+
+	ref := in.Spec.VolumeSnapshotRef
+	out.Spec.VolumeSnapshotRef = &ref
+	if in.Spec.VolumeSnapshotClassName != nil {
+		out.Spec.VolumeSnapshotClassName = *in.Spec.VolumeSnapshotClassName
+	}
+	out.Spec.DeletionPolicy = in.Spec.DeletionPolicy
+	return out, nil
+}
+
+// ConvertVolumeSnapshotClassToV1beta1 converts a v1alpha1 VolumeSnapshotClass to
+// v1beta1. v1alpha1 has no DeletionPolicy concept on VolumeSnapshotClass, so the
+// v1beta1 object (where DeletionPolicy is required) defaults to Delete, matching the
+// pre-DeletionPolicy behavior.
+func ConvertVolumeSnapshotClassToV1beta1(in *v1alpha1.VolumeSnapshotClass) (*v1beta1.VolumeSnapshotClass, error) {
+	out := &v1beta1.VolumeSnapshotClass{
+		TypeMeta:       in.TypeMeta,
+		ObjectMeta:     in.ObjectMeta,
+		Driver:         in.Snapshotter,
+		Parameters:     in.Parameters,
+		DeletionPolicy: v1beta1.VolumeSnapshotContentDelete,
+	}
+	out.TypeMeta.APIVersion = "snapshot.storage.k8s.io/v1beta1"
+	return out, nil
+}
+
+// ConvertVolumeSnapshotClassFromV1beta1 converts a v1beta1 VolumeSnapshotClass back
+// to v1alpha1. The DeletionPolicy carried by v1beta1 has no v1alpha1
+// VolumeSnapshotClass home and is dropped; callers that need it should read the
+// v1beta1 object directly.
+func ConvertVolumeSnapshotClassFromV1beta1(in *v1beta1.VolumeSnapshotClass) (*v1alpha1.VolumeSnapshotClass, error) {
+	out := &v1alpha1.VolumeSnapshotClass{
+		TypeMeta:    in.TypeMeta,
+		ObjectMeta:  in.ObjectMeta,
+		Snapshotter: in.Driver,
+		Parameters:  in.Parameters,
+	}
+	out.TypeMeta.APIVersion = "snapshot.storage.k8s.io/v1alpha1"
+	return out, nil
+}
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
+func int64Ptr(i int64) *int64 { return &i }
@@ -0,0 +1,167 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"testing"
+
+	core_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+)
+
+func TestConvertVolumeSnapshotRoundTrip(t *testing.T) {
+	in := &v1alpha1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Spec: v1alpha1.VolumeSnapshotSpec{
+			Source:                  &v1alpha1.TypedLocalObjectReference{Name: "pvc-1", Kind: "PersistentVolumeClaim"},
+			VolumeSnapshotClassName: "class-1",
+		},
+		Status: v1alpha1.VolumeSnapshotStatus{Ready: true},
+	}
+
+	beta, err := ConvertVolumeSnapshotToV1beta1(in)
+	if err != nil {
+		t.Fatalf("ConvertVolumeSnapshotToV1beta1: %v", err)
+	}
+	if beta.Spec.Source.PersistentVolumeClaimName == nil || *beta.Spec.Source.PersistentVolumeClaimName != "pvc-1" {
+		t.Fatalf("expected Source.PersistentVolumeClaimName pvc-1, got %+v", beta.Spec.Source)
+	}
+	if beta.Spec.VolumeSnapshotClassName == nil || *beta.Spec.VolumeSnapshotClassName != "class-1" {
+		t.Fatalf("expected VolumeSnapshotClassName class-1, got %+v", beta.Spec.VolumeSnapshotClassName)
+	}
+
+	back, err := ConvertVolumeSnapshotFromV1beta1(beta)
+	if err != nil {
+		t.Fatalf("ConvertVolumeSnapshotFromV1beta1: %v", err)
+	}
+	if back.Spec.Source == nil || back.Spec.Source.Name != "pvc-1" {
+		t.Fatalf("expected round-tripped Spec.Source.Name pvc-1, got %+v", back.Spec.Source)
+	}
+	if back.Spec.VolumeSnapshotClassName != "class-1" {
+		t.Fatalf("expected round-tripped VolumeSnapshotClassName class-1, got %q", back.Spec.VolumeSnapshotClassName)
+	}
+	if !back.Status.Ready {
+		t.Fatalf("expected round-tripped Status.Ready to stay true")
+	}
+}
+
+func TestConvertVolumeSnapshotNoSourceSet(t *testing.T) {
+	in := &v1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"}}
+	if _, err := ConvertVolumeSnapshotToV1beta1(in); err == nil {
+		t.Fatalf("expected an error when neither Spec.Source nor Spec.SnapshotContentName is set")
+	}
+}
+
+func TestConvertVolumeSnapshotContentRoundTrip(t *testing.T) {
+	in := &v1alpha1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: "content-1"},
+		Spec: v1alpha1.VolumeSnapshotContentSpec{
+			VolumeSnapshotSource: v1alpha1.VolumeSnapshotSource{
+				CSI: &v1alpha1.CSIVolumeSnapshotSource{
+					Driver:         "driver-1",
+					SnapshotHandle: "handle-1",
+					CreatedAt:      1234,
+					Size:           4096,
+				},
+			},
+			VolumeSnapshotRef:       &core_v1.ObjectReference{Namespace: "ns", Name: "snap-1"},
+			VolumeSnapshotClassName: "class-1",
+			DeletionPolicy:          v1beta1.VolumeSnapshotContentRetain,
+		},
+	}
+
+	beta, err := ConvertVolumeSnapshotContentToV1beta1(in)
+	if err != nil {
+		t.Fatalf("ConvertVolumeSnapshotContentToV1beta1: %v", err)
+	}
+	if beta.Spec.Driver != "driver-1" {
+		t.Fatalf("expected Driver driver-1, got %q", beta.Spec.Driver)
+	}
+	if beta.Spec.Source.SnapshotHandle == nil || *beta.Spec.Source.SnapshotHandle != "handle-1" {
+		t.Fatalf("expected Source.SnapshotHandle handle-1, got %+v", beta.Spec.Source)
+	}
+	if beta.Spec.DeletionPolicy != v1beta1.VolumeSnapshotContentRetain {
+		t.Fatalf("expected DeletionPolicy to survive conversion, got %q", beta.Spec.DeletionPolicy)
+	}
+	if beta.Status == nil || beta.Status.CreationTime == nil || *beta.Status.CreationTime != 1234 {
+		t.Fatalf("expected Status.CreationTime 1234, got %+v", beta.Status)
+	}
+	if beta.Status.RestoreSize == nil || *beta.Status.RestoreSize != 4096 {
+		t.Fatalf("expected Status.RestoreSize 4096, got %+v", beta.Status)
+	}
+
+	back, err := ConvertVolumeSnapshotContentFromV1beta1(beta)
+	if err != nil {
+		t.Fatalf("ConvertVolumeSnapshotContentFromV1beta1: %v", err)
+	}
+	if back.Spec.CSI == nil || back.Spec.CSI.SnapshotHandle != "handle-1" || back.Spec.CSI.Driver != "driver-1" {
+		t.Fatalf("expected round-tripped CSI source, got %+v", back.Spec.CSI)
+	}
+	if back.Spec.CSI.CreatedAt != 1234 || back.Spec.CSI.Size != 4096 {
+		t.Fatalf("expected round-tripped CreatedAt/Size, got %+v", back.Spec.CSI)
+	}
+	if back.Spec.DeletionPolicy != v1beta1.VolumeSnapshotContentRetain {
+		t.Fatalf("expected round-tripped DeletionPolicy Retain, got %q", back.Spec.DeletionPolicy)
+	}
+}
+
+func TestConvertVolumeSnapshotContentFromV1beta1RejectsVolumeHandleSource(t *testing.T) {
+	volumeHandle := "vol-1"
+	in := &v1beta1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: "content-1"},
+		Spec: v1beta1.VolumeSnapshotContentSpec{
+			Driver: "driver-1",
+			Source: v1beta1.VolumeSnapshotContentSource{VolumeHandle: &volumeHandle},
+		},
+	}
+	if _, err := ConvertVolumeSnapshotContentFromV1beta1(in); err == nil {
+		t.Fatalf("expected an error converting a VolumeHandle-sourced content back to v1alpha1")
+	}
+}
+
+func TestConvertVolumeSnapshotClassRoundTrip(t *testing.T) {
+	in := &v1alpha1.VolumeSnapshotClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "class-1"},
+		Snapshotter: "driver-1",
+		Parameters:  map[string]string{"foo": "bar"},
+	}
+
+	beta, err := ConvertVolumeSnapshotClassToV1beta1(in)
+	if err != nil {
+		t.Fatalf("ConvertVolumeSnapshotClassToV1beta1: %v", err)
+	}
+	if beta.Driver != "driver-1" {
+		t.Fatalf("expected Driver driver-1, got %q", beta.Driver)
+	}
+	if beta.DeletionPolicy != v1beta1.VolumeSnapshotContentDelete {
+		t.Fatalf("expected a v1alpha1 class with no DeletionPolicy concept to default to Delete, got %q", beta.DeletionPolicy)
+	}
+
+	back, err := ConvertVolumeSnapshotClassFromV1beta1(beta)
+	if err != nil {
+		t.Fatalf("ConvertVolumeSnapshotClassFromV1beta1: %v", err)
+	}
+	if back.Snapshotter != "driver-1" {
+		t.Fatalf("expected round-tripped Snapshotter driver-1, got %q", back.Snapshotter)
+	}
+	if back.Parameters["foo"] != "bar" {
+		t.Fatalf("expected round-tripped Parameters to survive, got %+v", back.Parameters)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+)
+
+// ServeVolumeSnapshotConversion is an http.HandlerFunc that implements the
+// CustomResourceConversion webhook contract for VolumeSnapshot, VolumeSnapshotContent
+// and VolumeSnapshotClass: it round-trips each object in the request between
+// v1alpha1 and v1beta1, dispatching on the object's own kind rather than assuming
+// every object in the request is a VolumeSnapshot.
+func ServeVolumeSnapshotConversion(w http.ResponseWriter, r *http.Request) {
+	review := &apiextensionsv1beta1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	response := &apiextensionsv1beta1.ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	converted := make([]runtime.RawExtension, 0, len(review.Request.Objects))
+	for _, obj := range review.Request.Objects {
+		out, err := convertVolumeSnapshotObject(obj.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			glog.Errorf("VolumeSnapshot conversion to %s failed: %v", review.Request.DesiredAPIVersion, err)
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		converted = append(converted, runtime.RawExtension{Raw: out})
+	}
+	response.ConvertedObjects = converted
+
+	review.Response = response
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// objectKind holds just enough of an object's TypeMeta to dispatch conversion by
+// kind, without committing to unmarshaling it as any one concrete type up front.
+type objectKind struct {
+	Kind string `json:"kind"`
+}
+
+func convertVolumeSnapshotObject(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	ok := objectKind{}
+	if err := json.Unmarshal(raw, &ok); err != nil {
+		return nil, err
+	}
+
+	switch desiredAPIVersion {
+	case "snapshot.storage.k8s.io/v1beta1":
+		return convertToV1beta1(raw, ok.Kind)
+	case "snapshot.storage.k8s.io/v1alpha1":
+		return convertFromV1beta1(raw, ok.Kind)
+	default:
+		return raw, nil
+	}
+}
+
+func convertToV1beta1(raw []byte, kind string) ([]byte, error) {
+	switch kind {
+	case "VolumeSnapshot":
+		in := &v1alpha1.VolumeSnapshot{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		out, err := ConvertVolumeSnapshotToV1beta1(in)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	case "VolumeSnapshotContent":
+		in := &v1alpha1.VolumeSnapshotContent{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		out, err := ConvertVolumeSnapshotContentToV1beta1(in)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	case "VolumeSnapshotClass":
+		in := &v1alpha1.VolumeSnapshotClass{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		out, err := ConvertVolumeSnapshotClassToV1beta1(in)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	default:
+		return nil, fmt.Errorf("conversion webhook does not know how to convert kind %q to v1beta1", kind)
+	}
+}
+
+func convertFromV1beta1(raw []byte, kind string) ([]byte, error) {
+	switch kind {
+	case "VolumeSnapshot":
+		in := &v1beta1.VolumeSnapshot{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		out, err := ConvertVolumeSnapshotFromV1beta1(in)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	case "VolumeSnapshotContent":
+		in := &v1beta1.VolumeSnapshotContent{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		out, err := ConvertVolumeSnapshotContentFromV1beta1(in)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	case "VolumeSnapshotClass":
+		in := &v1beta1.VolumeSnapshotClass{}
+		if err := json.Unmarshal(raw, in); err != nil {
+			return nil, err
+		}
+		out, err := ConvertVolumeSnapshotClassFromV1beta1(in)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(out)
+	default:
+		return nil, fmt.Errorf("conversion webhook does not know how to convert kind %q from v1beta1", kind)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	core_v1 "k8s.io/api/core/v1"
 	storage "k8s.io/api/storage/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
 )
 
 const (
@@ -29,6 +31,12 @@ const (
 	VolumeSnapshotResourcePlural = "volumesnapshots"
 	// VolumeSnapshotClassResourcePlural is "volumesnapshotclasses"
 	VolumeSnapshotClassResourcePlural = "volumesnapshotclasses"
+	// VolumeGroupSnapshotResourcePlural is "volumegroupsnapshots"
+	VolumeGroupSnapshotResourcePlural = "volumegroupsnapshots"
+	// VolumeGroupSnapshotContentResourcePlural is "volumegroupsnapshotcontents"
+	VolumeGroupSnapshotContentResourcePlural = "volumegroupsnapshotcontents"
+	// VolumeGroupSnapshotClassResourcePlural is "volumegroupsnapshotclasses"
+	VolumeGroupSnapshotClassResourcePlural = "volumegroupsnapshotclasses"
 )
 
 // +genclient
@@ -86,6 +94,200 @@ type VolumeSnapshotSpec struct {
 	Size int64 `json:"size,omitempty" protobuf:"varint,4,opt,name=size"`
 }
 
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshot is a user's request for a crash-consistent snapshot of a group
+// of PersistentVolumeClaims, created together as a single backend operation so that
+// the resulting snapshots are consistent with one another (e.g. the data and log
+// volumes of a database).
+type VolumeGroupSnapshot struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec defines the desired characteristics of a group snapshot requested by a user.
+	Spec VolumeGroupSnapshotSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+
+	// Status represents the latest observed state of the group snapshot
+	// +optional
+	Status VolumeGroupSnapshotStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotList is a list of VolumeGroupSnapshot objects
+type VolumeGroupSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Items is the list of VolumeGroupSnapshots
+	Items []VolumeGroupSnapshot `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeGroupSnapshotSpec describes the common attributes of a group snapshot.
+// Exactly one of Selector or PersistentVolumeClaimNames should be set to determine
+// the member PersistentVolumeClaims; if both are set, their intersection is used.
+type VolumeGroupSnapshotSpec struct {
+	// Selector selects the PersistentVolumeClaims that are members of this group by label.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty" protobuf:"bytes,1,opt,name=selector"`
+
+	// PersistentVolumeClaimNames lists the member PersistentVolumeClaims by name, in the
+	// same namespace as the VolumeGroupSnapshot. Use this when the members are not
+	// conveniently expressed as a label selector.
+	// +optional
+	PersistentVolumeClaimNames []string `json:"persistentVolumeClaimNames,omitempty" protobuf:"bytes,2,rep,name=persistentVolumeClaimNames"`
+
+	// Name of the VolumeGroupSnapshotClass used by the VolumeGroupSnapshot. If not
+	// specified, a default group snapshot class will be used if it is available.
+	// +optional
+	VolumeGroupSnapshotClassName string `json:"groupSnapshotClassName" protobuf:"bytes,3,opt,name=groupSnapshotClassName"`
+}
+
+// VolumeGroupSnapshotStatus is the status of the VolumeGroupSnapshot
+type VolumeGroupSnapshotStatus struct {
+	// VolumeSnapshotRefList is the list of VolumeSnapshots created for the individual
+	// members of the group. It is only populated once the group snapshot as a whole
+	// is Ready, since the group is created with all-or-nothing semantics.
+	// +optional
+	VolumeSnapshotRefList []core_v1.LocalObjectReference `json:"volumeSnapshotRefList,omitempty" protobuf:"bytes,1,rep,name=volumeSnapshotRefList"`
+
+	// CreationTime is the time the group snapshot was successfully created. If it is
+	// set, it means the group snapshot was created; Otherwise it was not created.
+	// +optional
+	CreationTime *metav1.Time `json:"createdAt" protobuf:"bytes,2,opt,name=createdAt"`
+
+	// Ready is set to true only once every snapshot in the group is ready to use.
+	// +optional
+	Ready bool `json:"ready" protobuf:"varint,3,opt,name=ready"`
+
+	// The last error encountered during the create group snapshot operation, if any.
+	// This field must only be set by the entity completing the create operation,
+	// i.e. the external-snapshotter.
+	// +optional
+	Error *storage.VolumeError `json:"error,omitempty" protobuf:"bytes,4,opt,name=error"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotClass describes the parameters used by the storage system when
+// provisioning a VolumeGroupSnapshot from a group of PVCs.
+// The name of a VolumeGroupSnapshotClass object is significant, and is how users can
+// request a particular class.
+type VolumeGroupSnapshotClass struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Snapshotter is the driver expected to handle this VolumeGroupSnapshotClass.
+	Snapshotter string `json:"snapshotter" protobuf:"bytes,2,opt,name=snapshotter"`
+
+	// Parameters holds parameters for the snapshotter.
+	// These values are opaque to the system and are passed directly
+	// to the snapshotter.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty" protobuf:"bytes,3,rep,name=parameters"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotClassList is a collection of group snapshot classes.
+type VolumeGroupSnapshotClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Items is the list of VolumeGroupSnapshotClasses
+	Items []VolumeGroupSnapshotClass `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotContent represents the actual "on-disk" group snapshot object,
+// i.e. the group of individual snapshots as they exist on the storage backend.
+type VolumeGroupSnapshotContent struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec represents the desired state of the group snapshot data
+	Spec VolumeGroupSnapshotContentSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeGroupSnapshotContentList is a list of VolumeGroupSnapshotContent objects
+type VolumeGroupSnapshotContentList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Items is the list of VolumeGroupSnapshotContents
+	Items []VolumeGroupSnapshotContent `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeGroupSnapshotContentSpec is the spec of the group snapshot data
+type VolumeGroupSnapshotContentSpec struct {
+	// VolumeGroupSnapshotRef is part of the bi-directional binding between
+	// VolumeGroupSnapshot and VolumeGroupSnapshotContent. It becomes non-nil when bound.
+	// +optional
+	VolumeGroupSnapshotRef *core_v1.ObjectReference `json:"volumeGroupSnapshotRef" protobuf:"bytes,1,opt,name=volumeGroupSnapshotRef"`
+
+	// PersistentVolumeRefList is the list of PersistentVolumes that the member
+	// snapshots were taken from, in the same order as VolumeSnapshotHandles.
+	// +optional
+	PersistentVolumeRefList []core_v1.ObjectReference `json:"persistentVolumeRefList,omitempty" protobuf:"bytes,2,rep,name=persistentVolumeRefList"`
+
+	// Name of the VolumeGroupSnapshotClass used by the VolumeGroupSnapshot. If not
+	// specified, a default group snapshot class will be used if it is available.
+	// +optional
+	VolumeGroupSnapshotClassName string `json:"groupSnapshotClassName" protobuf:"bytes,3,opt,name=groupSnapshotClassName"`
+
+	// Source represents the location and type of the group snapshot on the backend
+	// +optional
+	Source *CSIVolumeGroupSnapshotSource `json:"source,omitempty" protobuf:"bytes,4,opt,name=source"`
+}
+
+// CSIVolumeGroupSnapshotSource represents the source from a CSI group snapshot
+type CSIVolumeGroupSnapshotSource struct {
+	// Driver is the name of the driver to use for this group snapshot.
+	// This MUST be the same name returned by the CSI GetPluginName() call for
+	// that driver.
+	// Required.
+	Driver string `json:"driver" protobuf:"bytes,1,opt,name=driver"`
+
+	// VolumeGroupSnapshotHandle is the unique group snapshot id returned by the CSI
+	// volume driver's CreateVolumeGroupSnapshot to refer to the group on all
+	// subsequent calls.
+	// Required.
+	VolumeGroupSnapshotHandle string `json:"volumeGroupSnapshotHandle" protobuf:"bytes,2,opt,name=volumeGroupSnapshotHandle"`
+
+	// VolumeSnapshotHandles are the individual snapshot ids making up the group, as
+	// returned by the CSI volume driver.
+	VolumeSnapshotHandles []string `json:"volumeSnapshotHandles,omitempty" protobuf:"bytes,3,rep,name=volumeSnapshotHandles"`
+
+	// Timestamp when the point-in-time group snapshot is taken on the storage
+	// system. This timestamp will be generated by the CSI volume driver after
+	// the group snapshot is cut. The format of this field should be a Unix
+	// nanoseconds time encoded as an int64.
+	// +optional
+	CreatedAt int64 `json:"createdAt,omitempty" protobuf:"varint,4,opt,name=createdAt"`
+}
+
 // VolumeSnapshotStatus is the status of the VolumeSnapshot
 type VolumeSnapshotStatus struct {
 	// CreationTime is the time the snapshot was successfully created. If it is set,
@@ -203,6 +405,18 @@ type VolumeSnapshotContentSpec struct {
 	// be used if it is available.
 	// +optional
 	VolumeSnapshotClassName string `json:"snapshotClassName" protobuf:"bytes,4,opt,name=snapshotClassName"`
+
+	// DeletionPolicy tells the external-snapshotter whether to delete the snapshot on
+	// the storage backend (Delete) or only remove this Kubernetes object (Retain) when
+	// the bound VolumeSnapshot is deleted. This matters most for statically-bound,
+	// pre-provisioned snapshots that the external-snapshotter did not itself create.
+	// If not specified, Delete is assumed.
+	//
+	// This reuses v1beta1.DeletionPolicy, rather than forking an equivalent v1alpha1
+	// type, so a value set here survives the v1alpha1<->v1beta1 conversion in
+	// pkg/apis/conversion unchanged.
+	// +optional
+	DeletionPolicy v1beta1.DeletionPolicy `json:"deletionPolicy,omitempty" protobuf:"bytes,5,opt,name=deletionPolicy"`
 }
 
 // VolumeSnapshotSource represents the actual location and type of the snapshot. Only one of its members may be specified.
@@ -237,4 +451,4 @@ type CSIVolumeSnapshotSource struct {
 	// The complete size of the volume snapshot
 	// +optional
 	Size int64 `json:"size,omitempty" protobuf:"varint,4,opt,name=size"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// VolumeSnapshotContentResourcePlural is "volumesnapshotcontents"
+	VolumeSnapshotContentResourcePlural = "volumesnapshotcontents"
+	// VolumeSnapshotResourcePlural is "volumesnapshots"
+	VolumeSnapshotResourcePlural = "volumesnapshots"
+	// VolumeSnapshotClassResourcePlural is "volumesnapshotclasses"
+	VolumeSnapshotClassResourcePlural = "volumesnapshotclasses"
+)
+
+// DeletionPolicy describes a policy for end-of-life maintenance of a snapshot
+// backing a VolumeSnapshotContent.
+type DeletionPolicy string
+
+const (
+	// VolumeSnapshotContentDelete means the snapshot on the backend storage system
+	// is deleted when the bound VolumeSnapshot is deleted.
+	VolumeSnapshotContentDelete DeletionPolicy = "Delete"
+	// VolumeSnapshotContentRetain means the snapshot on the backend storage system
+	// is kept when the bound VolumeSnapshot is deleted. Only the Kubernetes object
+	// is removed.
+	VolumeSnapshotContentRetain DeletionPolicy = "Retain"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshot is a user's request for taking a snapshot. Upon successful creation of the actual
+// snapshot by the volume provider it is bound to the corresponding VolumeSnapshotContent.
+// Only the VolumeSnapshot object is accessible to the user in the namespace.
+type VolumeSnapshot struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec defines the desired characteristics of a snapshot requested by a user.
+	Spec VolumeSnapshotSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+
+	// Status represents the latest observed state of the snapshot
+	// +optional
+	Status *VolumeSnapshotStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotList is a list of VolumeSnapshot objects
+type VolumeSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Items is the list of VolumeSnapshots
+	Items []VolumeSnapshot `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeSnapshotSpec describes the common attributes of a volume snapshot
+type VolumeSnapshotSpec struct {
+	// Source specifies where a snapshot is (or should be) created from. Exactly one
+	// of its members must be set.
+	Source VolumeSnapshotSource `json:"source" protobuf:"bytes,1,opt,name=source"`
+
+	// Name of the VolumeSnapshotClass used by the VolumeSnapshot. If not specified, a
+	// default snapshot class will be used if it is available.
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty" protobuf:"bytes,2,opt,name=volumeSnapshotClassName"`
+}
+
+// VolumeSnapshotSource specifies whether the underlying snapshot should be dynamically
+// taken from a PersistentVolumeClaim or already exists as a pre-provisioned
+// VolumeSnapshotContent. Exactly one of its members must be set; it is immutable
+// after creation.
+type VolumeSnapshotSource struct {
+	// PersistentVolumeClaimName is the name of the PVC, in the same namespace as the
+	// VolumeSnapshot, to dynamically take a snapshot of.
+	// +optional
+	PersistentVolumeClaimName *string `json:"persistentVolumeClaimName,omitempty" protobuf:"bytes,1,opt,name=persistentVolumeClaimName"`
+
+	// VolumeSnapshotContentName binds this VolumeSnapshot to a pre-provisioned
+	// VolumeSnapshotContent.
+	// +optional
+	VolumeSnapshotContentName *string `json:"volumeSnapshotContentName,omitempty" protobuf:"bytes,2,opt,name=volumeSnapshotContentName"`
+}
+
+// VolumeSnapshotStatus is the status of the VolumeSnapshot
+type VolumeSnapshotStatus struct {
+	// BoundVolumeSnapshotContentName is the name of the VolumeSnapshotContent object this
+	// VolumeSnapshot is bound to. Once this field is set, it is immutable.
+	// +optional
+	BoundVolumeSnapshotContentName *string `json:"boundVolumeSnapshotContentName,omitempty" protobuf:"bytes,1,opt,name=boundVolumeSnapshotContentName"`
+
+	// CreationTime is the time the snapshot was successfully created. If it is set,
+	// it means the snapshot was created; Otherwise the snapshot was not created.
+	// +optional
+	CreationTime *metav1.Time `json:"creationTime,omitempty" protobuf:"bytes,2,opt,name=creationTime"`
+
+	// ReadyToUse is set to true only if the snapshot is ready to use (e.g., finish
+	// uploading if there is an uploading phase) and also VolumeSnapshot and its
+	// VolumeSnapshotContent bind correctly with each other.
+	// +optional
+	ReadyToUse *bool `json:"readyToUse,omitempty" protobuf:"varint,3,opt,name=readyToUse"`
+
+	// RestoreSize is the minimum size of volume required to rehydrate from this snapshot.
+	// +optional
+	RestoreSize *int64 `json:"restoreSize,omitempty" protobuf:"varint,4,opt,name=restoreSize"`
+
+	// Error is the last observed error during create snapshot operation, if any.
+	// +optional
+	Error *VolumeSnapshotError `json:"error,omitempty" protobuf:"bytes,5,opt,name=error"`
+}
+
+// VolumeSnapshotError describes an error encountered during a snapshot operation.
+type VolumeSnapshotError struct {
+	// Time is the timestamp when the error was encountered.
+	// +optional
+	Time *metav1.Time `json:"time,omitempty" protobuf:"bytes,1,opt,name=time"`
+
+	// Message is a string detailing the encountered error during snapshot creation if
+	// specified. NOTE: message may be logged, and it should not contain sensitive data.
+	// +optional
+	Message *string `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotClass describes the parameters used by storage system when
+// provisioning VolumeSnapshots from PVCs.
+// The name of a VolumeSnapshotClass object is significant, and is how users can request a particular class.
+type VolumeSnapshotClass struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Driver is the name of the storage driver expected to handle this VolumeSnapshotClass.
+	Driver string `json:"driver" protobuf:"bytes,2,opt,name=driver"`
+
+	// Parameters holds parameters for the snapshotter.
+	// These values are opaque to the system and are passed directly
+	// to the snapshotter.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty" protobuf:"bytes,3,rep,name=parameters"`
+
+	// DeletionPolicy determines whether the snapshot on the backend storage system
+	// should be deleted (Delete) or kept (Retain) when its bound VolumeSnapshot is
+	// deleted. Required.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy" protobuf:"bytes,4,opt,name=deletionPolicy"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotClassList is a collection of snapshot classes.
+type VolumeSnapshotClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Items is the list of VolumeSnapshotClasses
+	Items []VolumeSnapshotClass `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotContent represents the actual "on-disk" snapshot object
+type VolumeSnapshotContent struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Spec represents the desired state of the snapshot data
+	Spec VolumeSnapshotContentSpec `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+
+	// Status represents the latest observed state of the snapshot content
+	// +optional
+	Status *VolumeSnapshotContentStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VolumeSnapshotContentList is a list of VolumeSnapshotContent objects
+type VolumeSnapshotContentList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty" protobuf:"bytes,1,opt,name=metadata"`
+
+	// Items is the list of VolumeSnapshotContents
+	Items []VolumeSnapshotContent `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// VolumeSnapshotContentSpec is the spec of the volume snapshot data
+type VolumeSnapshotContentSpec struct {
+	// VolumeSnapshotRef is part of bi-directional binding between VolumeSnapshot
+	// and VolumeSnapshotContent.
+	VolumeSnapshotRef core_v1.ObjectReference `json:"volumeSnapshotRef" protobuf:"bytes,1,opt,name=volumeSnapshotRef"`
+
+	// Source specifies whether the snapshot is (or should be) dynamically provisioned
+	// from a PersistentVolume or already exists on the backend as a pre-provisioned
+	// snapshot. Exactly one of its members must be set.
+	Source VolumeSnapshotContentSource `json:"source" protobuf:"bytes,2,opt,name=source"`
+
+	// Name of the VolumeSnapshotClass used to create this content. If not specified,
+	// a default snapshot class will be used if it is available.
+	// +optional
+	VolumeSnapshotClassName *string `json:"volumeSnapshotClassName,omitempty" protobuf:"bytes,3,opt,name=volumeSnapshotClassName"`
+
+	// DeletionPolicy determines whether the snapshot on the backend storage system
+	// should be deleted (Delete) or kept (Retain) when the bound VolumeSnapshot is
+	// deleted. Required.
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy" protobuf:"bytes,4,opt,name=deletionPolicy"`
+
+	// Driver is the name of the CSI driver expected to handle this VolumeSnapshotContent.
+	Driver string `json:"driver" protobuf:"bytes,5,opt,name=driver"`
+}
+
+// VolumeSnapshotContentSource specifies the origin of a VolumeSnapshotContent: either a
+// PersistentVolume to dynamically snapshot, or a pre-existing backend snapshot handle to
+// import. Exactly one of its members must be set.
+type VolumeSnapshotContentSource struct {
+	// VolumeHandle is the CSI volume handle of the PersistentVolume to dynamically
+	// create a snapshot of.
+	// +optional
+	VolumeHandle *string `json:"volumeHandle,omitempty" protobuf:"bytes,1,opt,name=volumeHandle"`
+
+	// SnapshotHandle is the CSI snapshot id of an already existing snapshot on the
+	// backend to statically import.
+	// +optional
+	SnapshotHandle *string `json:"snapshotHandle,omitempty" protobuf:"bytes,2,opt,name=snapshotHandle"`
+}
+
+// VolumeSnapshotContentStatus is the status of the VolumeSnapshotContent
+type VolumeSnapshotContentStatus struct {
+	// SnapshotHandle is the unique snapshot id returned by the CSI driver to refer to
+	// the snapshot on all subsequent calls.
+	// +optional
+	SnapshotHandle *string `json:"snapshotHandle,omitempty" protobuf:"bytes,1,opt,name=snapshotHandle"`
+
+	// CreationTime is a Unix nanoseconds timestamp, generated by the CSI driver once
+	// the snapshot is cut.
+	// +optional
+	CreationTime *int64 `json:"creationTime,omitempty" protobuf:"varint,2,opt,name=creationTime"`
+
+	// RestoreSize is the minimum size of volume required to rehydrate from this snapshot.
+	// +optional
+	RestoreSize *int64 `json:"restoreSize,omitempty" protobuf:"varint,3,opt,name=restoreSize"`
+
+	// ReadyToUse indicates if the snapshot is ready to be used to restore a volume.
+	// +optional
+	ReadyToUse *bool `json:"readyToUse,omitempty" protobuf:"varint,4,opt,name=readyToUse"`
+
+	// Error is the last observed error during create snapshot operation, if any.
+	// +optional
+	Error *VolumeSnapshotError `json:"error,omitempty" protobuf:"bytes,5,opt,name=error"`
+}
@@ -54,8 +54,28 @@ type CSIConnection interface {
 	// DeleteSnapshot deletes a snapshot from a volume
 	DeleteSnapshot(ctx context.Context, snapshotID string, snapshotterCredentials map[string]string) (err error)
 
-	// GetSnapshotStatus returns if a snapshot is ready to use, creation time, and restore size.
-	GetSnapshotStatus(ctx context.Context, snapshotID string) (bool, int64, int64, error)
+	// GetSnapshotStatus returns if a snapshot is ready to use, creation time, and restore
+	// size. sourceVolumeID narrows the ListSnapshots lookup and may be empty. secrets is
+	// passed through to ListSnapshotsRequest.Secrets. Results are served from a
+	// short-lived in-process cache when available.
+	GetSnapshotStatus(ctx context.Context, snapshotID string, sourceVolumeID string, secrets map[string]string) (bool, int64, int64, error)
+
+	// SupportsGroupSnapshot returns true if the CSI driver reports
+	// CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT in GroupControllerGetCapabilities() gRPC call.
+	SupportsGroupSnapshot(ctx context.Context) (bool, error)
+
+	// CreateGroupSnapshot creates a crash-consistent snapshot of a group of volumes.
+	// It returns the backend-assigned group ID together with the individual snapshots
+	// the driver created as part of the group.
+	CreateGroupSnapshot(ctx context.Context, groupSnapshotName string, volumes []*v1.PersistentVolume, parameters map[string]string, secrets map[string]string) (groupID string, snapshots []Snapshot, readyToUse bool, creationTime int64, err error)
+
+	// DeleteGroupSnapshot deletes a group snapshot, and the individual snapshots
+	// that belong to it, from the backend.
+	DeleteGroupSnapshot(ctx context.Context, groupID string, snapshotIDs []string, secrets map[string]string) (err error)
+
+	// GetGroupSnapshotStatus returns the individual snapshots belonging to a group
+	// snapshot and whether the group as a whole is ready to use.
+	GetGroupSnapshotStatus(ctx context.Context, groupID string) (readyToUse bool, creationTime int64, snapshots []Snapshot, err error)
 
 	// Probe checks that the CSI driver is ready to process requests
 	Probe(ctx context.Context) error
@@ -64,21 +84,49 @@ type CSIConnection interface {
 	Close() error
 }
 
+// Snapshot describes a single snapshot belonging to a group snapshot, as reported by
+// the CSI driver's GroupController RPCs.
+type Snapshot struct {
+	// SnapshotID is the backend id of this individual snapshot.
+	SnapshotID string
+	// SourceVolumeID is the backend id of the volume this snapshot was taken from.
+	SourceVolumeID string
+	// CreationTime is a Unix nanoseconds timestamp, as returned by the driver.
+	CreationTime int64
+	// SizeBytes is the size of the snapshot, as returned by the driver.
+	SizeBytes int64
+	// ReadyToUse reports whether this individual snapshot is ready to use.
+	ReadyToUse bool
+}
+
 type csiConnection struct {
-	conn *grpc.ClientConn
+	conn        *grpc.ClientConn
+	statusCache *snapshotStatusCache
 }
 
 var (
 	_ CSIConnection = &csiConnection{}
 )
 
+// defaultSnapshotStatusCacheTTL bounds how long a GetSnapshotStatus result is trusted
+// before the driver is asked again, so the controller's periodic resync does not issue
+// one ListSnapshots call per VolumeSnapshot on every resync.
+const defaultSnapshotStatusCacheTTL = 1 * time.Minute
+
 func New(address string, timeout time.Duration) (CSIConnection, error) {
+	return NewWithCacheTTL(address, timeout, defaultSnapshotStatusCacheTTL)
+}
+
+// NewWithCacheTTL is like New but lets the caller tune how long GetSnapshotStatus
+// results are cached before the driver is asked again.
+func NewWithCacheTTL(address string, timeout time.Duration, statusCacheTTL time.Duration) (CSIConnection, error) {
 	conn, err := connect(address, timeout)
 	if err != nil {
 		return nil, err
 	}
 	return &csiConnection{
-		conn: conn,
+		conn:        conn,
+		statusCache: newSnapshotStatusCache(statusCacheTTL),
 	}, nil
 }
 
@@ -239,27 +287,177 @@ func (c *csiConnection) DeleteSnapshot(ctx context.Context, snapshotID string, s
 	return nil
 }
 
-func (c *csiConnection) GetSnapshotStatus(ctx context.Context, snapshotID string) (bool, int64, int64, error) {
-	client := csi.NewControllerClient(c.conn)
+func (c *csiConnection) GetSnapshotStatus(ctx context.Context, snapshotID string, sourceVolumeID string, secrets map[string]string) (bool, int64, int64, error) {
+	if status, ok := c.statusCache.get(snapshotID); ok {
+		return status.ReadyToUse, status.CreationTime, status.SizeBytes, nil
+	}
 
+	status, err := c.getSnapshotStatusFromDriver(ctx, snapshotID, sourceVolumeID, secrets)
+	if err != nil {
+		return false, 0, 0, err
+	}
+
+	c.statusCache.set(snapshotID, status)
+	return status.ReadyToUse, status.CreationTime, status.SizeBytes, nil
+}
+
+func (c *csiConnection) getSnapshotStatusFromDriver(ctx context.Context, snapshotID string, sourceVolumeID string, secrets map[string]string) (snapshotStatus, error) {
+	client := csi.NewControllerClient(c.conn)
 	req := csi.ListSnapshotsRequest{
-		SnapshotId: snapshotID,
+		SnapshotId:     snapshotID,
+		SourceVolumeId: sourceVolumeID,
+		Secrets:        secrets,
+	}
+
+	for {
+		rsp, err := client.ListSnapshots(ctx, &req)
+		if err != nil {
+			return snapshotStatus{}, err
+		}
+
+		for _, entry := range rsp.Entries {
+			if entry.Snapshot == nil {
+				continue
+			}
+			creationTime, err := timestampToUnixTime(entry.Snapshot.CreationTime)
+			if err != nil {
+				return snapshotStatus{}, err
+			}
+			return snapshotStatus{
+				ReadyToUse:   entry.Snapshot.ReadyToUse,
+				CreationTime: creationTime,
+				SizeBytes:    entry.Snapshot.SizeBytes,
+			}, nil
+		}
+
+		if rsp.NextToken == "" {
+			return snapshotStatus{}, fmt.Errorf("can not find snapshot for snapshotID %s", snapshotID)
+		}
+		req.StartingToken = rsp.NextToken
 	}
+}
+
+func (c *csiConnection) SupportsGroupSnapshot(ctx context.Context) (bool, error) {
+	client := csi.NewGroupControllerClient(c.conn)
+	req := csi.GroupControllerGetCapabilitiesRequest{}
 
-	rsp, err := client.ListSnapshots(ctx, &req)
+	rsp, err := client.GroupControllerGetCapabilities(ctx, &req)
 	if err != nil {
-		return false, 0, 0, err
+		return false, err
+	}
+	caps := rsp.GetCapabilities()
+	for _, cap := range caps {
+		if cap == nil {
+			continue
+		}
+		rpc := cap.GetRpc()
+		if rpc == nil {
+			continue
+		}
+		if rpc.GetType() == csi.GroupControllerServiceCapability_RPC_CREATE_DELETE_GET_VOLUME_GROUP_SNAPSHOT {
+			return true, nil
+		}
 	}
+	return false, nil
+}
 
-	if rsp.Entries == nil || len(rsp.Entries) == 0 {
-		return false, 0, 0, fmt.Errorf("can not find snapshot for snapshotID %s", snapshotID)
+func (c *csiConnection) CreateGroupSnapshot(ctx context.Context, groupSnapshotName string, volumes []*v1.PersistentVolume, parameters map[string]string, secrets map[string]string) (string, []Snapshot, bool, int64, error) {
+	glog.V(5).Infof("CSI CreateGroupSnapshot: %s", groupSnapshotName)
+	volumeIDs := make([]string, 0, len(volumes))
+	for _, volume := range volumes {
+		if volume.Spec.CSI == nil {
+			return "", nil, false, 0, fmt.Errorf("CSIPersistentVolumeSource not defined in spec for volume %s", volume.Name)
+		}
+		volumeIDs = append(volumeIDs, volume.Spec.CSI.VolumeHandle)
 	}
 
-	creationTime, err := timestampToUnixTime(rsp.Entries[0].Snapshot.CreationTime)
+	client := csi.NewGroupControllerClient(c.conn)
+
+	req := csi.CreateVolumeGroupSnapshotRequest{
+		Name:            groupSnapshotName,
+		SourceVolumeIds: volumeIDs,
+		Parameters:      parameters,
+		Secrets:         secrets,
+	}
+
+	rsp, err := client.CreateVolumeGroupSnapshot(ctx, &req)
 	if err != nil {
-		return false, 0, 0, err
+		return "", nil, false, 0, err
+	}
+
+	creationTime, err := timestampToUnixTime(rsp.GroupSnapshot.CreationTime)
+	if err != nil {
+		return "", nil, false, 0, err
+	}
+
+	snapshots, err := groupMemberSnapshots(rsp.GroupSnapshot.Snapshots)
+	if err != nil {
+		return "", nil, false, 0, err
+	}
+
+	glog.V(5).Infof("CSI CreateGroupSnapshot: %s group ID [%s] readyToUse [%v] with %d member snapshots", groupSnapshotName, rsp.GroupSnapshot.GroupSnapshotId, rsp.GroupSnapshot.ReadyToUse, len(snapshots))
+	return rsp.GroupSnapshot.GroupSnapshotId, snapshots, rsp.GroupSnapshot.ReadyToUse, creationTime, nil
+}
+
+func (c *csiConnection) DeleteGroupSnapshot(ctx context.Context, groupID string, snapshotIDs []string, secrets map[string]string) error {
+	client := csi.NewGroupControllerClient(c.conn)
+
+	req := csi.DeleteVolumeGroupSnapshotRequest{
+		GroupSnapshotId: groupID,
+		SnapshotIds:     snapshotIDs,
+		Secrets:         secrets,
+	}
+
+	if _, err := client.DeleteVolumeGroupSnapshot(ctx, &req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *csiConnection) GetGroupSnapshotStatus(ctx context.Context, groupID string) (bool, int64, []Snapshot, error) {
+	client := csi.NewGroupControllerClient(c.conn)
+
+	req := csi.GetVolumeGroupSnapshotRequest{
+		GroupSnapshotId: groupID,
+	}
+
+	rsp, err := client.GetVolumeGroupSnapshot(ctx, &req)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	creationTime, err := timestampToUnixTime(rsp.GroupSnapshot.CreationTime)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	snapshots, err := groupMemberSnapshots(rsp.GroupSnapshot.Snapshots)
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	return rsp.GroupSnapshot.ReadyToUse, creationTime, snapshots, nil
+}
+
+// groupMemberSnapshots converts the per-member entries of a CSI group snapshot
+// response into the connection package's own Snapshot type.
+func groupMemberSnapshots(entries []*csi.Snapshot) ([]Snapshot, error) {
+	snapshots := make([]Snapshot, 0, len(entries))
+	for _, entry := range entries {
+		creationTime, err := timestampToUnixTime(entry.CreationTime)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, Snapshot{
+			SnapshotID:     entry.SnapshotId,
+			SourceVolumeID: entry.SourceVolumeId,
+			CreationTime:   creationTime,
+			SizeBytes:      entry.SizeBytes,
+			ReadyToUse:     entry.ReadyToUse,
+		})
 	}
-	return rsp.Entries[0].Snapshot.ReadyToUse, creationTime, rsp.Entries[0].Snapshot.SizeBytes, nil
+	return snapshots, nil
 }
 
 func (c *csiConnection) Close() error {
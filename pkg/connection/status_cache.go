@@ -0,0 +1,120 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// maxSnapshotStatusCacheEntries bounds the number of snapshots the status cache will
+// track at once; once full, the least-recently-used entry is evicted to make room for
+// a new one, so the cache can't grow without bound over a long-running controller
+// process even if entries are never explicitly invalidated.
+const maxSnapshotStatusCacheEntries = 2048
+
+// snapshotStatus is the cached result of a GetSnapshotStatus lookup.
+type snapshotStatus struct {
+	ReadyToUse   bool
+	CreationTime int64
+	SizeBytes    int64
+}
+
+// snapshotStatusCache is a small in-process, size-bounded LRU cache of
+// GetSnapshotStatus results keyed by snapshot ID, with TTL-based expiry on top. It
+// exists so that a controller's periodic resync of many VolumeSnapshots doesn't issue
+// one ListSnapshots call per snapshot per resync; unchanged snapshots are served from
+// cache until the entry's TTL expires or it is evicted to make room for others.
+type snapshotStatusCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List // front = most recently used
+	entries map[string]*list.Element
+}
+
+type cachedSnapshotStatus struct {
+	snapshotID string
+	status     snapshotStatus
+	observedAt time.Time
+}
+
+func newSnapshotStatusCache(ttl time.Duration) *snapshotStatusCache {
+	return &snapshotStatusCache{
+		ttl:        ttl,
+		maxEntries: maxSnapshotStatusCacheEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+func (c *snapshotStatusCache) get(snapshotID string) (snapshotStatus, bool) {
+	if c == nil || c.ttl <= 0 {
+		return snapshotStatus{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[snapshotID]
+	if !ok {
+		return snapshotStatus{}, false
+	}
+	entry := elem.Value.(*cachedSnapshotStatus)
+	if time.Since(entry.observedAt) > c.ttl {
+		c.removeElementLocked(elem)
+		return snapshotStatus{}, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.status, true
+}
+
+func (c *snapshotStatusCache) set(snapshotID string, status snapshotStatus) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[snapshotID]; ok {
+		elem.Value.(*cachedSnapshotStatus).status = status
+		elem.Value.(*cachedSnapshotStatus).observedAt = time.Now()
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&cachedSnapshotStatus{
+		snapshotID: snapshotID,
+		status:     status,
+		observedAt: time.Now(),
+	})
+	c.entries[snapshotID] = elem
+
+	for c.order.Len() > c.maxEntries {
+		c.removeElementLocked(c.order.Back())
+	}
+}
+
+// removeElementLocked removes elem from both the LRU list and the lookup map. The
+// caller must hold c.mu.
+func (c *snapshotStatusCache) removeElementLocked(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cachedSnapshotStatus).snapshotID)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connection
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotStatusCacheGetSet(t *testing.T) {
+	c := newSnapshotStatusCache(time.Minute)
+
+	if _, ok := c.get("snap-1"); ok {
+		t.Fatalf("expected miss on empty cache")
+	}
+
+	want := snapshotStatus{ReadyToUse: true, CreationTime: 42, SizeBytes: 1024}
+	c.set("snap-1", want)
+
+	got, ok := c.get("snap-1")
+	if !ok {
+		t.Fatalf("expected hit after set")
+	}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestSnapshotStatusCacheExpiry(t *testing.T) {
+	c := newSnapshotStatusCache(time.Millisecond)
+	c.set("snap-1", snapshotStatus{ReadyToUse: true})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.get("snap-1"); ok {
+		t.Fatalf("expected entry to have expired")
+	}
+	if _, ok := c.entries["snap-1"]; ok {
+		t.Fatalf("expected expired entry to be evicted from the map, not just hidden")
+	}
+}
+
+func TestSnapshotStatusCacheDisabled(t *testing.T) {
+	c := newSnapshotStatusCache(0)
+	c.set("snap-1", snapshotStatus{ReadyToUse: true})
+
+	if _, ok := c.get("snap-1"); ok {
+		t.Fatalf("a zero TTL cache should never return a hit")
+	}
+}
+
+func TestSnapshotStatusCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newSnapshotStatusCache(time.Minute)
+	c.maxEntries = 2
+
+	c.set("snap-1", snapshotStatus{SizeBytes: 1})
+	c.set("snap-2", snapshotStatus{SizeBytes: 2})
+
+	// Touch snap-1 so it is more recently used than snap-2.
+	if _, ok := c.get("snap-1"); !ok {
+		t.Fatalf("expected snap-1 to still be cached")
+	}
+
+	// Adding a third entry should evict snap-2, the least-recently-used one.
+	c.set("snap-3", snapshotStatus{SizeBytes: 3})
+
+	if _, ok := c.get("snap-2"); ok {
+		t.Fatalf("expected snap-2 to have been evicted")
+	}
+	if _, ok := c.get("snap-1"); !ok {
+		t.Fatalf("expected snap-1 to still be cached")
+	}
+	if _, ok := c.get("snap-3"); !ok {
+		t.Fatalf("expected snap-3 to be cached")
+	}
+	if c.order.Len() != 2 {
+		t.Fatalf("expected cache to hold exactly maxEntries entries, got %d", c.order.Len())
+	}
+}
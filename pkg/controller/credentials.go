@@ -0,0 +1,65 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/connection"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/credentials"
+)
+
+// CreateSnapshot resolves the VolumeSnapshotClass's snapshotter Secret (if any) and
+// drives the CSI CreateSnapshot call, passing the resolved credentials and a
+// parameters map with the recognized secret keys stripped out so the driver never
+// sees them.
+func CreateSnapshot(ctx context.Context, kubeClient kubernetes.Interface, conn connection.CSIConnection, snapshotName string, volume *v1.PersistentVolume, class *crdv1alpha1.VolumeSnapshotClass, snap *crdv1alpha1.VolumeSnapshot) (string, string, int64, int64, bool, error) {
+	creds, err := credentials.GetCredentialsFromClass(ctx, kubeClient, class, snap, nil)
+	if err != nil {
+		return "", "", 0, 0, false, fmt.Errorf("failed to get credentials for snapshot %s: %v", snapshotName, err)
+	}
+
+	parameters := credentials.StripRecognizedParameters(class.Parameters)
+	return conn.CreateSnapshot(ctx, snapshotName, volume, parameters, creds)
+}
+
+// DeleteSnapshot resolves the VolumeSnapshotClass's snapshotter Secret (if any) and
+// drives the CSI DeleteSnapshot call with the resolved credentials.
+func DeleteSnapshot(ctx context.Context, kubeClient kubernetes.Interface, conn connection.CSIConnection, snapshotID string, class *crdv1alpha1.VolumeSnapshotClass, snap *crdv1alpha1.VolumeSnapshot, content *crdv1alpha1.VolumeSnapshotContent) error {
+	creds, err := credentials.GetCredentialsFromClass(ctx, kubeClient, class, snap, content)
+	if err != nil {
+		return fmt.Errorf("failed to get credentials for snapshot %s: %v", snapshotID, err)
+	}
+
+	return conn.DeleteSnapshot(ctx, snapshotID, creds)
+}
+
+// GetSnapshotStatus resolves the VolumeSnapshotClass's snapshotter-list Secret (if any)
+// and drives the CSI GetSnapshotStatus call with the resolved credentials.
+func GetSnapshotStatus(ctx context.Context, kubeClient kubernetes.Interface, conn connection.CSIConnection, snapshotID string, sourceVolumeID string, class *crdv1alpha1.VolumeSnapshotClass, snap *crdv1alpha1.VolumeSnapshot, content *crdv1alpha1.VolumeSnapshotContent) (bool, int64, int64, error) {
+	creds, err := credentials.GetListCredentialsFromClass(ctx, kubeClient, class, snap, content)
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("failed to get list credentials for snapshot %s: %v", snapshotID, err)
+	}
+
+	return conn.GetSnapshotStatus(ctx, snapshotID, sourceVolumeID, creds)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/golang/glog"
+
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/connection"
+)
+
+// DeleteVolumeSnapshotContent deletes a VolumeSnapshotContent's underlying CSI
+// snapshot, honoring its DeletionPolicy: the backend snapshot is only deleted when
+// the policy is Delete. With Retain, the backend snapshot is left alone and only the
+// Kubernetes object is expected to be removed by the caller.
+func DeleteVolumeSnapshotContent(ctx context.Context, conn connection.CSIConnection, content *v1beta1.VolumeSnapshotContent, secrets map[string]string) error {
+	if content.Spec.DeletionPolicy != v1beta1.VolumeSnapshotContentDelete {
+		glog.V(4).Infof("VolumeSnapshotContent %s has DeletionPolicy %s, skipping backend snapshot deletion", content.Name, content.Spec.DeletionPolicy)
+		return nil
+	}
+
+	if content.Status == nil || content.Status.SnapshotHandle == nil {
+		glog.V(4).Infof("VolumeSnapshotContent %s has no snapshot handle, nothing to delete on the backend", content.Name)
+		return nil
+	}
+
+	return conn.DeleteSnapshot(ctx, *content.Status.SnapshotHandle, secrets)
+}
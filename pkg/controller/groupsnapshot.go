@@ -0,0 +1,89 @@
+/*
+Copyright 2018 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/connection"
+)
+
+// CreateVolumeGroupSnapshot drives a single CSI group snapshot creation through to
+// completion with all-or-nothing semantics: if the backend reports the group as
+// created but any member snapshot came back incomplete, the whole group is torn
+// down again via DeleteGroupSnapshot so a partially created group is never left
+// behind on the storage system.
+func CreateVolumeGroupSnapshot(ctx context.Context, conn connection.CSIConnection, groupSnapshotName string, volumes []*v1.PersistentVolume, parameters map[string]string, secrets map[string]string) (*crdv1alpha1.VolumeGroupSnapshotStatus, error) {
+	groupID, snapshots, readyToUse, creationTime, err := conn.CreateGroupSnapshot(ctx, groupSnapshotName, volumes, parameters, secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateGroupMembers(snapshots, len(volumes)); err != nil {
+		rollbackGroupSnapshot(ctx, conn, groupSnapshotName, groupID, snapshots, secrets)
+		return nil, err
+	}
+
+	refs := make([]v1.LocalObjectReference, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		refs = append(refs, v1.LocalObjectReference{Name: snapshot.SnapshotID})
+	}
+
+	t := metav1.Unix(0, creationTime)
+	return &crdv1alpha1.VolumeGroupSnapshotStatus{
+		VolumeSnapshotRefList: refs,
+		CreationTime:          &t,
+		Ready:                 readyToUse,
+	}, nil
+}
+
+// validateGroupMembers checks that the backend returned exactly one ready member
+// snapshot per source volume; anything less means the group is unusable.
+func validateGroupMembers(snapshots []connection.Snapshot, wantMembers int) error {
+	if len(snapshots) != wantMembers {
+		return fmt.Errorf("expected %d member snapshots, backend returned %d", wantMembers, len(snapshots))
+	}
+	for _, snapshot := range snapshots {
+		if snapshot.SnapshotID == "" {
+			return fmt.Errorf("backend returned a member snapshot with no snapshot ID for source volume %s", snapshot.SourceVolumeID)
+		}
+	}
+	return nil
+}
+
+// rollbackGroupSnapshot deletes a group snapshot that was only partially created on
+// the backend. Failures are logged rather than returned, since the caller already has
+// the original error to report and a failed rollback should not mask it.
+func rollbackGroupSnapshot(ctx context.Context, conn connection.CSIConnection, groupSnapshotName, groupID string, snapshots []connection.Snapshot, secrets map[string]string) {
+	if groupID == "" {
+		return
+	}
+	ids := make([]string, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		ids = append(ids, snapshot.SnapshotID)
+	}
+	glog.Errorf("group snapshot %q was created incompletely, rolling back group %q", groupSnapshotName, groupID)
+	if err := conn.DeleteGroupSnapshot(ctx, groupID, ids, secrets); err != nil {
+		glog.Errorf("failed to roll back incomplete group snapshot %q (group %q): %v", groupSnapshotName, groupID, err)
+	}
+}
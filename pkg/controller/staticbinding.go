@@ -0,0 +1,149 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	core_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/connection"
+)
+
+const (
+	// VolumeSnapshotBoundProtectionFinalizer guards a VolumeSnapshot against deletion
+	// while it is still being bound to a VolumeSnapshotContent.
+	VolumeSnapshotBoundProtectionFinalizer = "snapshot.storage.kubernetes.io/volumesnapshot-bound-protection"
+	// VolumeSnapshotContentBoundProtectionFinalizer guards a VolumeSnapshotContent
+	// against deletion while it is still bound to a VolumeSnapshot.
+	VolumeSnapshotContentBoundProtectionFinalizer = "snapshot.storage.kubernetes.io/volumesnapshotcontent-bound-protection"
+)
+
+// ClaimVolumeSnapshotContent implements step (b) of statically binding a
+// VolumeSnapshot to a pre-provisioned VolumeSnapshotContent: if the content's
+// VolumeSnapshotRef is unset, it is claimed on behalf of snap; if it already points
+// elsewhere, binding is rejected so the caller can surface an event.
+//
+// As a side effect, it attaches VolumeSnapshotBoundProtectionFinalizer /
+// VolumeSnapshotContentBoundProtectionFinalizer to snap and content so the API server
+// refuses to delete either object while the bind is still in flight; the caller is
+// responsible for persisting both objects if ClaimVolumeSnapshotContent returns
+// without error.
+func ClaimVolumeSnapshotContent(snap *crdv1alpha1.VolumeSnapshot, content *crdv1alpha1.VolumeSnapshotContent) (*core_v1.ObjectReference, error) {
+	if content.Spec.VolumeSnapshotRef != nil {
+		ref := content.Spec.VolumeSnapshotRef
+		if ref.Namespace != snap.Namespace || ref.Name != snap.Name {
+			return nil, fmt.Errorf("VolumeSnapshotContent %s is already bound to VolumeSnapshot %s/%s, refusing to rebind it to %s/%s", content.Name, ref.Namespace, ref.Name, snap.Namespace, snap.Name)
+		}
+		addFinalizer(&snap.Finalizers, VolumeSnapshotBoundProtectionFinalizer)
+		addFinalizer(&content.Finalizers, VolumeSnapshotContentBoundProtectionFinalizer)
+		return ref, nil
+	}
+
+	ref := &core_v1.ObjectReference{
+		Kind:      "VolumeSnapshot",
+		Namespace: snap.Namespace,
+		Name:      snap.Name,
+		UID:       snap.UID,
+	}
+	addFinalizer(&snap.Finalizers, VolumeSnapshotBoundProtectionFinalizer)
+	addFinalizer(&content.Finalizers, VolumeSnapshotContentBoundProtectionFinalizer)
+	return ref, nil
+}
+
+// addFinalizer appends finalizer to *finalizers if it isn't already present.
+func addFinalizer(finalizers *[]string, finalizer string) {
+	for _, f := range *finalizers {
+		if f == finalizer {
+			return
+		}
+	}
+	*finalizers = append(*finalizers, finalizer)
+}
+
+// removeFinalizer removes finalizer from *finalizers, if present.
+func removeFinalizer(finalizers *[]string, finalizer string) {
+	kept := (*finalizers)[:0]
+	for _, f := range *finalizers {
+		if f != finalizer {
+			kept = append(kept, f)
+		}
+	}
+	*finalizers = kept
+}
+
+// BindStaticSnapshot implements steps (c) and (d) of statically binding a
+// VolumeSnapshot that set SnapshotContentName and no Source: it looks up the status
+// of the pre-provisioned snapshot on the backend via GetSnapshotStatus, using the
+// content's CSIVolumeSnapshotSource.SnapshotHandle, instead of calling CreateSnapshot.
+// secrets is passed through to GetSnapshotStatus and should be resolved by the caller
+// via credentials.GetListCredentialsFromClass, the same way callers of DeleteSnapshot
+// resolve theirs via credentials.GetCredentialsFromClass.
+func BindStaticSnapshot(ctx context.Context, conn connection.CSIConnection, content *crdv1alpha1.VolumeSnapshotContent, secrets map[string]string) (*crdv1alpha1.VolumeSnapshotStatus, error) {
+	if content.Spec.CSI == nil || content.Spec.CSI.SnapshotHandle == "" {
+		return nil, fmt.Errorf("VolumeSnapshotContent %s has no CSI SnapshotHandle to statically bind to", content.Name)
+	}
+
+	// PersistentVolumeRef.Name is the Kubernetes PersistentVolume object's metadata
+	// name, not a CSI backend volume handle; v1alpha1 VolumeSnapshotContent has no
+	// field carrying the real Spec.CSI.VolumeHandle, so SourceVolumeId is left empty
+	// (it's optional) rather than passing through a value the driver can't match.
+	readyToUse, creationTime, _, err := conn.GetSnapshotStatus(ctx, content.Spec.CSI.SnapshotHandle, "", secrets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status of pre-provisioned snapshot %s: %v", content.Spec.CSI.SnapshotHandle, err)
+	}
+
+	t := metav1.Unix(0, creationTime)
+	return &crdv1alpha1.VolumeSnapshotStatus{
+		CreationTime: &t,
+		Ready:        readyToUse,
+	}, nil
+}
+
+// DeleteStaticVolumeSnapshotContent deletes a v1alpha1 VolumeSnapshotContent's
+// underlying CSI snapshot, honoring DeletionPolicy: the backend snapshot is only
+// deleted when the policy is Delete (the default for an empty/unset policy, to match
+// pre-DeletionPolicy behavior), so importing a pre-provisioned snapshot with
+// DeletionPolicy=Retain and later deleting the Kubernetes object never destroys the
+// underlying data.
+//
+// It also removes VolumeSnapshotContentBoundProtectionFinalizer from content once the
+// backend snapshot has been handled (deleted, or retained by policy), so the caller can
+// persist the update and let the API server complete the object's deletion. The
+// finalizer is left in place if the backend delete fails, so a retry doesn't let the
+// object disappear before the underlying snapshot is actually gone. It is the caller's
+// responsibility to do the same for the bound VolumeSnapshot's
+// VolumeSnapshotBoundProtectionFinalizer once that object is also being removed.
+func DeleteStaticVolumeSnapshotContent(ctx context.Context, conn connection.CSIConnection, content *crdv1alpha1.VolumeSnapshotContent, secrets map[string]string) error {
+	if content.Spec.DeletionPolicy == v1beta1.VolumeSnapshotContentRetain {
+		removeFinalizer(&content.Finalizers, VolumeSnapshotContentBoundProtectionFinalizer)
+		return nil
+	}
+	if content.Spec.CSI == nil || content.Spec.CSI.SnapshotHandle == "" {
+		removeFinalizer(&content.Finalizers, VolumeSnapshotContentBoundProtectionFinalizer)
+		return nil
+	}
+	if err := conn.DeleteSnapshot(ctx, content.Spec.CSI.SnapshotHandle, secrets); err != nil {
+		return err
+	}
+	removeFinalizer(&content.Finalizers, VolumeSnapshotContentBoundProtectionFinalizer)
+	return nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+	"github.com/kubernetes-csi/external-snapshotter/pkg/validation"
+)
+
+// validateBeforeUpdate runs the same rules the snapshot-validator webhook enforces
+// at admission time, so a controller-driven mutation can never write an object back
+// to the API server that the webhook would have rejected.
+func validateBeforeUpdate(snap *v1beta1.VolumeSnapshot) error {
+	if diags := validation.ValidateVolumeSnapshot(snap); len(diags) > 0 {
+		return fmt.Errorf("refusing to update VolumeSnapshot %s: %s", diags[0].Object, diags[0].Message)
+	}
+	return nil
+}
+
+// BindVolumeSnapshotToContent prepares the VolumeSnapshotStatus update that binds
+// snap to a VolumeSnapshotContent named contentName, going through
+// validateBeforeUpdate first so the controller can never write back a VolumeSnapshot
+// the admission webhook would have rejected. This is the v1beta1 counterpart of
+// ClaimVolumeSnapshotContent for statically-bound pre-provisioned snapshots.
+func BindVolumeSnapshotToContent(snap *v1beta1.VolumeSnapshot, contentName string) (*v1beta1.VolumeSnapshotStatus, error) {
+	if err := validateBeforeUpdate(snap); err != nil {
+		return nil, err
+	}
+	return &v1beta1.VolumeSnapshotStatus{
+		BoundVolumeSnapshotContentName: &contentName,
+	}, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentials resolves the Secret a VolumeSnapshotClass points a CSI
+// snapshotter call at, following the secret-templating convention used across the
+// CSI ecosystem (external-provisioner, external-attacher, etc).
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+)
+
+const (
+	// PrefixedSnapshotterSecretNameKey is the parameter key a VolumeSnapshotClass uses
+	// to name the Secret passed to CreateSnapshot/DeleteSnapshot.
+	PrefixedSnapshotterSecretNameKey = "csi.storage.k8s.io/snapshotter-secret-name"
+	// PrefixedSnapshotterSecretNamespaceKey is the parameter key a VolumeSnapshotClass
+	// uses to namespace the Secret passed to CreateSnapshot/DeleteSnapshot.
+	PrefixedSnapshotterSecretNamespaceKey = "csi.storage.k8s.io/snapshotter-secret-namespace"
+
+	// PrefixedSnapshotterListSecretNameKey is the parameter key a VolumeSnapshotClass
+	// uses to name the Secret passed to GetSnapshotStatus/ListSnapshots.
+	PrefixedSnapshotterListSecretNameKey = "csi.storage.k8s.io/snapshotter-list-secret-name"
+	// PrefixedSnapshotterListSecretNamespaceKey is the parameter key a
+	// VolumeSnapshotClass uses to namespace the Secret passed to
+	// GetSnapshotStatus/ListSnapshots.
+	PrefixedSnapshotterListSecretNamespaceKey = "csi.storage.k8s.io/snapshotter-list-secret-namespace"
+
+	contentNameTemplate       = "${volumesnapshotcontent.name}"
+	snapshotNameTemplate      = "${volumesnapshot.name}"
+	snapshotNamespaceTemplate = "${volumesnapshot.namespace}"
+)
+
+// recognizedParameterKeys are the VolumeSnapshotClass.Parameters keys consumed here
+// rather than passed down to the CSI driver.
+var recognizedParameterKeys = map[string]bool{
+	PrefixedSnapshotterSecretNameKey:          true,
+	PrefixedSnapshotterSecretNamespaceKey:     true,
+	PrefixedSnapshotterListSecretNameKey:      true,
+	PrefixedSnapshotterListSecretNamespaceKey: true,
+}
+
+// GetCredentialsFromClass resolves the Secret referenced by class.Parameters (using
+// the snapshotter-secret-* keys) into a map suitable for a CSI CreateSnapshot or
+// DeleteSnapshot call, and strips the recognized keys out of a copy of
+// class.Parameters so the driver never sees them. content may be nil when no
+// VolumeSnapshotContent exists yet (e.g. before CreateSnapshot).
+func GetCredentialsFromClass(ctx context.Context, kubeClient kubernetes.Interface, class *crdv1alpha1.VolumeSnapshotClass, snap *crdv1alpha1.VolumeSnapshot, content *crdv1alpha1.VolumeSnapshotContent) (map[string]string, error) {
+	return getCredentials(ctx, kubeClient, class, snap, content, PrefixedSnapshotterSecretNameKey, PrefixedSnapshotterSecretNamespaceKey)
+}
+
+// GetListCredentialsFromClass resolves the Secret referenced by class.Parameters
+// (using the snapshotter-list-secret-* keys) for GetSnapshotStatus/ListSnapshots calls.
+func GetListCredentialsFromClass(ctx context.Context, kubeClient kubernetes.Interface, class *crdv1alpha1.VolumeSnapshotClass, snap *crdv1alpha1.VolumeSnapshot, content *crdv1alpha1.VolumeSnapshotContent) (map[string]string, error) {
+	return getCredentials(ctx, kubeClient, class, snap, content, PrefixedSnapshotterListSecretNameKey, PrefixedSnapshotterListSecretNamespaceKey)
+}
+
+func getCredentials(ctx context.Context, kubeClient kubernetes.Interface, class *crdv1alpha1.VolumeSnapshotClass, snap *crdv1alpha1.VolumeSnapshot, content *crdv1alpha1.VolumeSnapshotContent, nameKey, namespaceKey string) (map[string]string, error) {
+	nameTemplate, ok := class.Parameters[nameKey]
+	if !ok || nameTemplate == "" {
+		// No secret configured for this class; nothing to resolve.
+		return nil, nil
+	}
+	namespaceTemplate, ok := class.Parameters[namespaceKey]
+	if !ok || namespaceTemplate == "" {
+		return nil, fmt.Errorf("%s is set but %s is not", nameKey, namespaceKey)
+	}
+
+	name := substituteTemplate(nameTemplate, snap, content)
+	namespace := substituteTemplate(namespaceTemplate, snap, content)
+	if name == "" || namespace == "" {
+		return nil, fmt.Errorf("resolved empty secret name/namespace from templates %q/%q", nameTemplate, namespaceTemplate)
+	}
+
+	secret, err := kubeClient.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %v", namespace, name, err)
+	}
+
+	credentials := map[string]string{}
+	for k, v := range secret.Data {
+		credentials[k] = string(v)
+	}
+	return credentials, nil
+}
+
+// substituteTemplate replaces the well-known ${...} placeholders in a parameter
+// template with values from the VolumeSnapshot/VolumeSnapshotContent being processed.
+// content may be nil, in which case ${volumesnapshotcontent.name} is left untouched
+// since the content may not exist yet.
+func substituteTemplate(template string, snap *crdv1alpha1.VolumeSnapshot, content *crdv1alpha1.VolumeSnapshotContent) string {
+	out := template
+	if snap != nil {
+		out = strings.ReplaceAll(out, snapshotNameTemplate, snap.Name)
+		out = strings.ReplaceAll(out, snapshotNamespaceTemplate, snap.Namespace)
+	}
+	if content != nil {
+		out = strings.ReplaceAll(out, contentNameTemplate, content.Name)
+	}
+	return out
+}
+
+// StripRecognizedParameters returns a copy of parameters with the recognized
+// snapshotter-secret-* keys removed, suitable for passing to CreateSnapshotRequest.
+func StripRecognizedParameters(parameters map[string]string) map[string]string {
+	stripped := make(map[string]string, len(parameters))
+	for k, v := range parameters {
+		if recognizedParameterKeys[k] {
+			continue
+		}
+		stripped[k] = v
+	}
+	return stripped
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"testing"
+
+	core_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	crdv1alpha1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1alpha1"
+)
+
+func TestSubstituteTemplate(t *testing.T) {
+	snap := &crdv1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1"}}
+	content := &crdv1alpha1.VolumeSnapshotContent{ObjectMeta: metav1.ObjectMeta{Name: "content-1"}}
+
+	got := substituteTemplate("${volumesnapshot.namespace}-${volumesnapshot.name}-${volumesnapshotcontent.name}", snap, content)
+	want := "ns-1-snap-1-content-1"
+	if got != want {
+		t.Fatalf("substituteTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestSubstituteTemplateNilContentLeavesPlaceholder(t *testing.T) {
+	snap := &crdv1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1"}}
+
+	got := substituteTemplate("${volumesnapshotcontent.name}", snap, nil)
+	if got != contentNameTemplate {
+		t.Fatalf("substituteTemplate() = %q, want the placeholder left untouched (%q)", got, contentNameTemplate)
+	}
+}
+
+func TestGetCredentialsFromClassNotConfigured(t *testing.T) {
+	class := &crdv1alpha1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "class-1"}}
+	snap := &crdv1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1"}}
+
+	creds, err := GetCredentialsFromClass(context.TODO(), fake.NewSimpleClientset(), class, snap, nil)
+	if err != nil {
+		t.Fatalf("expected no error when the class configures no secret, got %v", err)
+	}
+	if creds != nil {
+		t.Fatalf("expected nil credentials when the class configures no secret, got %+v", creds)
+	}
+}
+
+func TestGetCredentialsFromClassMissingNamespaceKey(t *testing.T) {
+	class := &crdv1alpha1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-1"},
+		Parameters: map[string]string{PrefixedSnapshotterSecretNameKey: "secret-1"},
+	}
+	snap := &crdv1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1"}}
+
+	if _, err := GetCredentialsFromClass(context.TODO(), fake.NewSimpleClientset(), class, snap, nil); err == nil {
+		t.Fatalf("expected an error when the name key is set but the namespace key is not")
+	}
+}
+
+func TestGetCredentialsFromClassEmptyResolution(t *testing.T) {
+	class := &crdv1alpha1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-1"},
+		Parameters: map[string]string{
+			PrefixedSnapshotterSecretNameKey:      "",
+			PrefixedSnapshotterSecretNamespaceKey: "ns-1",
+		},
+	}
+	snap := &crdv1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1"}}
+
+	if _, err := GetCredentialsFromClass(context.TODO(), fake.NewSimpleClientset(), class, snap, nil); err != nil {
+		t.Fatalf("expected the no-secret-configured path (empty name key treated as unset) to return no error, got %v", err)
+	}
+}
+
+func TestGetCredentialsFromClassResolvesSecret(t *testing.T) {
+	secret := &core_v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1-secret"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+
+	class := &crdv1alpha1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-1"},
+		Parameters: map[string]string{
+			PrefixedSnapshotterSecretNameKey:      "${volumesnapshot.name}-secret",
+			PrefixedSnapshotterSecretNamespaceKey: "${volumesnapshot.namespace}",
+		},
+	}
+	snap := &crdv1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1"}}
+
+	creds, err := GetCredentialsFromClass(context.TODO(), kubeClient, class, snap, nil)
+	if err != nil {
+		t.Fatalf("GetCredentialsFromClass: %v", err)
+	}
+	if creds["key"] != "value" {
+		t.Fatalf("expected resolved credentials to contain key=value, got %+v", creds)
+	}
+}
+
+func TestGetListCredentialsFromClassUsesListSecretKeys(t *testing.T) {
+	secret := &core_v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "list-secret"},
+		Data:       map[string][]byte{"key": []byte("value")},
+	}
+	kubeClient := fake.NewSimpleClientset(secret)
+
+	class := &crdv1alpha1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-1"},
+		Parameters: map[string]string{
+			PrefixedSnapshotterListSecretNameKey:      "list-secret",
+			PrefixedSnapshotterListSecretNamespaceKey: "ns-1",
+		},
+	}
+	snap := &crdv1alpha1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "snap-1"}}
+
+	creds, err := GetListCredentialsFromClass(context.TODO(), kubeClient, class, snap, nil)
+	if err != nil {
+		t.Fatalf("GetListCredentialsFromClass: %v", err)
+	}
+	if creds["key"] != "value" {
+		t.Fatalf("expected resolved credentials to contain key=value, got %+v", creds)
+	}
+}
+
+func TestStripRecognizedParameters(t *testing.T) {
+	parameters := map[string]string{
+		PrefixedSnapshotterSecretNameKey:          "secret-1",
+		PrefixedSnapshotterSecretNamespaceKey:     "ns-1",
+		PrefixedSnapshotterListSecretNameKey:      "list-secret-1",
+		PrefixedSnapshotterListSecretNamespaceKey: "ns-1",
+		"driver-specific-key":                     "value",
+	}
+
+	stripped := StripRecognizedParameters(parameters)
+	if len(stripped) != 1 || stripped["driver-specific-key"] != "value" {
+		t.Fatalf("expected only the driver-specific key to survive, got %+v", stripped)
+	}
+}
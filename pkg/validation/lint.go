@@ -0,0 +1,88 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"bufio"
+	"bytes"
+
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+)
+
+// LintYAML runs the offline rules (1)-(4) over every VolumeSnapshot,
+// VolumeSnapshotContent and VolumeSnapshotClass document found in a multi-document
+// YAML file, without needing a live cluster. Rule (5), which needs to check whether a
+// bound VolumeSnapshotContent still exists, is skipped in offline mode. Documents of
+// other kinds are ignored.
+func LintYAML(data []byte) ([]Diagnostic, error) {
+	var diags []Diagnostic
+	reader := yaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for {
+		doc, err := reader.Read()
+		if err != nil {
+			break
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+
+		kind, err := kindOf(doc)
+		if err != nil {
+			diags = append(diags, Diagnostic{Rule: "parse", Message: err.Error()})
+			continue
+		}
+
+		switch kind {
+		case "VolumeSnapshot":
+			snap := &v1beta1.VolumeSnapshot{}
+			if err := yaml.Unmarshal(doc, snap); err != nil {
+				diags = append(diags, Diagnostic{Rule: "parse", Message: err.Error()})
+				continue
+			}
+			diags = append(diags, ValidateVolumeSnapshot(snap)...)
+		case "VolumeSnapshotContent":
+			content := &v1beta1.VolumeSnapshotContent{}
+			if err := yaml.Unmarshal(doc, content); err != nil {
+				diags = append(diags, Diagnostic{Rule: "parse", Message: err.Error()})
+				continue
+			}
+			diags = append(diags, ValidateVolumeSnapshotContent(content)...)
+		case "VolumeSnapshotClass":
+			class := &v1beta1.VolumeSnapshotClass{}
+			if err := yaml.Unmarshal(doc, class); err != nil {
+				diags = append(diags, Diagnostic{Rule: "parse", Message: err.Error()})
+				continue
+			}
+			diags = append(diags, ValidateVolumeSnapshotClass(class, nil)...)
+		}
+	}
+	return diags, nil
+}
+
+type typeMeta struct {
+	Kind string `json:"kind"`
+}
+
+func kindOf(doc []byte) (string, error) {
+	tm := typeMeta{}
+	if err := yaml.Unmarshal(doc, &tm); err != nil {
+		return "", err
+	}
+	return tm.Kind, nil
+}
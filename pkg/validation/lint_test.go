@@ -0,0 +1,102 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import "testing"
+
+func TestLintYAMLMultiDocumentDispatch(t *testing.T) {
+	data := []byte(`
+apiVersion: snapshot.storage.k8s.io/v1beta1
+kind: VolumeSnapshot
+metadata:
+  namespace: ns
+  name: snap-1
+spec: {}
+---
+apiVersion: snapshot.storage.k8s.io/v1beta1
+kind: VolumeSnapshotContent
+metadata:
+  name: content-1
+spec: {}
+---
+apiVersion: snapshot.storage.k8s.io/v1beta1
+kind: VolumeSnapshotClass
+metadata:
+  name: class-1
+driver: ""
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+`)
+
+	diags, err := LintYAML(data)
+	if err != nil {
+		t.Fatalf("LintYAML: %v", err)
+	}
+
+	rules := map[string]bool{}
+	for _, d := range diags {
+		rules[d.Rule] = true
+	}
+	for _, want := range []string{"source-exactly-one", "content-source-exactly-one", "class-driver-set"} {
+		if !rules[want] {
+			t.Fatalf("expected a %s diagnostic among %+v", want, diags)
+		}
+	}
+	if len(diags) != 3 {
+		t.Fatalf("expected exactly 3 diagnostics (one per malformed document, ConfigMap ignored), got %+v", diags)
+	}
+}
+
+func TestLintYAMLNoDiagnosticsForValidDocument(t *testing.T) {
+	data := []byte(`
+apiVersion: snapshot.storage.k8s.io/v1beta1
+kind: VolumeSnapshot
+metadata:
+  namespace: ns
+  name: snap-1
+spec:
+  source:
+    persistentVolumeClaimName: pvc-1
+`)
+
+	diags, err := LintYAML(data)
+	if err != nil {
+		t.Fatalf("LintYAML: %v", err)
+	}
+	if len(diags) != 0 {
+		t.Fatalf("expected no diagnostics for a valid VolumeSnapshot, got %+v", diags)
+	}
+}
+
+func TestLintYAMLParseError(t *testing.T) {
+	data := []byte(`
+apiVersion: snapshot.storage.k8s.io/v1beta1
+kind: VolumeSnapshot
+metadata: [this is not a map]
+`)
+
+	diags, err := LintYAML(data)
+	if err != nil {
+		t.Fatalf("LintYAML: %v", err)
+	}
+	if len(diags) != 1 || diags[0].Rule != "parse" {
+		t.Fatalf("expected a single parse diagnostic, got %+v", diags)
+	}
+}
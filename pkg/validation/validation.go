@@ -0,0 +1,181 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation implements the rules a VolumeSnapshot object must satisfy,
+// shared between the snapshot-validator ValidatingAdmissionWebhook and its offline
+// linter mode, and by the controller before it writes a mutated object back to the
+// API server.
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+)
+
+// Diagnostic is a single rule violation, keyed by the object it was found on so
+// offline lint output can be grouped per object for CI gating.
+type Diagnostic struct {
+	Object  string `json:"object"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// allowedReservedParameterKeys is the allow-list of "csi.storage.k8s.io/"-prefixed
+// VolumeSnapshotClass.Parameters keys recognized by the external-snapshotter itself.
+// Any other key under the reserved prefix is almost certainly a typo of one of these.
+var allowedReservedParameterKeys = map[string]bool{
+	"csi.storage.k8s.io/snapshotter-secret-name":           true,
+	"csi.storage.k8s.io/snapshotter-secret-namespace":      true,
+	"csi.storage.k8s.io/snapshotter-list-secret-name":      true,
+	"csi.storage.k8s.io/snapshotter-list-secret-namespace": true,
+}
+
+const reservedParameterPrefix = "csi.storage.k8s.io/"
+
+// ValidateVolumeSnapshot checks rule (1): Spec.Source must set exactly one of
+// PersistentVolumeClaimName/VolumeSnapshotContentName.
+func ValidateVolumeSnapshot(snap *v1beta1.VolumeSnapshot) []Diagnostic {
+	var diags []Diagnostic
+	object := objectName(snap.Namespace, snap.Name)
+
+	pvc := snap.Spec.Source.PersistentVolumeClaimName
+	content := snap.Spec.Source.VolumeSnapshotContentName
+	if (pvc == nil) == (content == nil) {
+		diags = append(diags, Diagnostic{
+			Object:  object,
+			Rule:    "source-exactly-one",
+			Message: "Spec.Source must set exactly one of PersistentVolumeClaimName or VolumeSnapshotContentName",
+		})
+	}
+	return diags
+}
+
+// ValidateVolumeSnapshotUpdate additionally checks that Spec.Source did not change,
+// since it is immutable after creation.
+func ValidateVolumeSnapshotUpdate(oldSnap, newSnap *v1beta1.VolumeSnapshot) []Diagnostic {
+	diags := ValidateVolumeSnapshot(newSnap)
+	if !sourceEqual(oldSnap.Spec.Source, newSnap.Spec.Source) {
+		diags = append(diags, Diagnostic{
+			Object:  objectName(newSnap.Namespace, newSnap.Name),
+			Rule:    "source-immutable",
+			Message: "Spec.Source is immutable after creation",
+		})
+	}
+	return diags
+}
+
+func sourceEqual(a, b v1beta1.VolumeSnapshotSource) bool {
+	return strPtrEqual(a.PersistentVolumeClaimName, b.PersistentVolumeClaimName) &&
+		strPtrEqual(a.VolumeSnapshotContentName, b.VolumeSnapshotContentName)
+}
+
+// ValidateVolumeSnapshotContent checks rule (2): Spec.Source must set exactly one of
+// a pre-provisioned SnapshotHandle or a dynamic VolumeHandle.
+func ValidateVolumeSnapshotContent(content *v1beta1.VolumeSnapshotContent) []Diagnostic {
+	var diags []Diagnostic
+	object := objectName("", content.Name)
+
+	handle := content.Spec.Source.SnapshotHandle
+	volume := content.Spec.Source.VolumeHandle
+	if (handle == nil) == (volume == nil) {
+		diags = append(diags, Diagnostic{
+			Object:  object,
+			Rule:    "content-source-exactly-one",
+			Message: "Spec.Source must set exactly one of SnapshotHandle or VolumeHandle",
+		})
+	}
+
+	return diags
+}
+
+// ValidateVolumeSnapshotBinding checks rule (5): a bound VolumeSnapshot whose
+// VolumeSnapshotContent has been deleted should be flagged as invalid. contentExists
+// is nil when running offline without cluster access to check.
+func ValidateVolumeSnapshotBinding(snap *v1beta1.VolumeSnapshot, contentExists func(name string) (bool, error)) []Diagnostic {
+	var diags []Diagnostic
+	if snap.Status == nil || snap.Status.BoundVolumeSnapshotContentName == nil || contentExists == nil {
+		return diags
+	}
+
+	name := *snap.Status.BoundVolumeSnapshotContentName
+	exists, err := contentExists(name)
+	if err != nil {
+		diags = append(diags, Diagnostic{
+			Object:  objectName(snap.Namespace, snap.Name),
+			Rule:    "bound-content-exists",
+			Message: fmt.Sprintf("could not verify VolumeSnapshotContent %s still exists: %v", name, err),
+		})
+		return diags
+	}
+	if !exists {
+		diags = append(diags, Diagnostic{
+			Object:  objectName(snap.Namespace, snap.Name),
+			Rule:    "bound-content-exists",
+			Message: fmt.Sprintf("bound VolumeSnapshotContent %s no longer exists", name),
+		})
+	}
+	return diags
+}
+
+// ValidateVolumeSnapshotClass checks rules (3) and (4): Driver must be a valid,
+// installed CSI driver name, and reserved parameter keys must be on the allow-list.
+// knownDrivers is nil when running offline without cluster access to check installed
+// CSIDriver objects.
+func ValidateVolumeSnapshotClass(class *v1beta1.VolumeSnapshotClass, knownDrivers map[string]bool) []Diagnostic {
+	var diags []Diagnostic
+	object := objectName("", class.Name)
+
+	if class.Driver == "" {
+		diags = append(diags, Diagnostic{
+			Object:  object,
+			Rule:    "class-driver-set",
+			Message: "Driver must not be empty",
+		})
+	} else if knownDrivers != nil && !knownDrivers[class.Driver] {
+		diags = append(diags, Diagnostic{
+			Object:  object,
+			Rule:    "class-driver-installed",
+			Message: fmt.Sprintf("Driver %q does not match any installed CSIDriver object", class.Driver),
+		})
+	}
+
+	for key := range class.Parameters {
+		if strings.HasPrefix(key, reservedParameterPrefix) && !allowedReservedParameterKeys[key] {
+			diags = append(diags, Diagnostic{
+				Object:  object,
+				Rule:    "class-parameter-allowlist",
+				Message: fmt.Sprintf("parameter key %q uses the reserved %q prefix but is not a recognized key", key, reservedParameterPrefix),
+			})
+		}
+	}
+	return diags
+}
+
+func objectName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+func strPtrEqual(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,196 @@
+/*
+Copyright 2019 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1beta1 "github.com/kubernetes-csi/external-snapshotter/pkg/apis/volumesnapshot/v1beta1"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestValidateVolumeSnapshotSourceExactlyOne(t *testing.T) {
+	snap := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Spec:       v1beta1.VolumeSnapshotSpec{Source: v1beta1.VolumeSnapshotSource{PersistentVolumeClaimName: strPtr("pvc-1")}},
+	}
+	if diags := ValidateVolumeSnapshot(snap); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics for a single source, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotSourceNeitherSet(t *testing.T) {
+	snap := &v1beta1.VolumeSnapshot{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"}}
+	diags := ValidateVolumeSnapshot(snap)
+	if len(diags) != 1 || diags[0].Rule != "source-exactly-one" {
+		t.Fatalf("expected a single source-exactly-one diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotSourceBothSet(t *testing.T) {
+	snap := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Spec: v1beta1.VolumeSnapshotSpec{Source: v1beta1.VolumeSnapshotSource{
+			PersistentVolumeClaimName: strPtr("pvc-1"),
+			VolumeSnapshotContentName: strPtr("content-1"),
+		}},
+	}
+	diags := ValidateVolumeSnapshot(snap)
+	if len(diags) != 1 || diags[0].Rule != "source-exactly-one" {
+		t.Fatalf("expected a single source-exactly-one diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotUpdateSourceImmutable(t *testing.T) {
+	old := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Spec:       v1beta1.VolumeSnapshotSpec{Source: v1beta1.VolumeSnapshotSource{PersistentVolumeClaimName: strPtr("pvc-1")}},
+	}
+	newSnap := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Spec:       v1beta1.VolumeSnapshotSpec{Source: v1beta1.VolumeSnapshotSource{PersistentVolumeClaimName: strPtr("pvc-2")}},
+	}
+	diags := ValidateVolumeSnapshotUpdate(old, newSnap)
+	if len(diags) != 1 || diags[0].Rule != "source-immutable" {
+		t.Fatalf("expected a single source-immutable diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotUpdateSourceUnchanged(t *testing.T) {
+	old := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Spec:       v1beta1.VolumeSnapshotSpec{Source: v1beta1.VolumeSnapshotSource{PersistentVolumeClaimName: strPtr("pvc-1")}},
+	}
+	newSnap := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Spec:       v1beta1.VolumeSnapshotSpec{Source: v1beta1.VolumeSnapshotSource{PersistentVolumeClaimName: strPtr("pvc-1")}},
+	}
+	if diags := ValidateVolumeSnapshotUpdate(old, newSnap); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics when Spec.Source is unchanged, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotContentSourceExactlyOne(t *testing.T) {
+	both := &v1beta1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: "content-1"},
+		Spec: v1beta1.VolumeSnapshotContentSpec{Source: v1beta1.VolumeSnapshotContentSource{
+			SnapshotHandle: strPtr("handle-1"),
+			VolumeHandle:   strPtr("vol-1"),
+		}},
+	}
+	diags := ValidateVolumeSnapshotContent(both)
+	if len(diags) != 1 || diags[0].Rule != "content-source-exactly-one" {
+		t.Fatalf("expected a single content-source-exactly-one diagnostic, got %+v", diags)
+	}
+
+	neither := &v1beta1.VolumeSnapshotContent{ObjectMeta: metav1.ObjectMeta{Name: "content-1"}}
+	diags = ValidateVolumeSnapshotContent(neither)
+	if len(diags) != 1 || diags[0].Rule != "content-source-exactly-one" {
+		t.Fatalf("expected a single content-source-exactly-one diagnostic, got %+v", diags)
+	}
+
+	one := &v1beta1.VolumeSnapshotContent{
+		ObjectMeta: metav1.ObjectMeta{Name: "content-1"},
+		Spec:       v1beta1.VolumeSnapshotContentSpec{Source: v1beta1.VolumeSnapshotContentSource{SnapshotHandle: strPtr("handle-1")}},
+	}
+	if diags := ValidateVolumeSnapshotContent(one); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics with exactly one source set, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotBindingSkippedWithoutContentExists(t *testing.T) {
+	name := "content-1"
+	snap := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Status:     &v1beta1.VolumeSnapshotStatus{BoundVolumeSnapshotContentName: &name},
+	}
+	if diags := ValidateVolumeSnapshotBinding(snap, nil); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics when contentExists is nil, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotBindingMissingContent(t *testing.T) {
+	name := "content-1"
+	snap := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Status:     &v1beta1.VolumeSnapshotStatus{BoundVolumeSnapshotContentName: &name},
+	}
+	diags := ValidateVolumeSnapshotBinding(snap, func(string) (bool, error) { return false, nil })
+	if len(diags) != 1 || diags[0].Rule != "bound-content-exists" {
+		t.Fatalf("expected a single bound-content-exists diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotBindingCheckError(t *testing.T) {
+	name := "content-1"
+	snap := &v1beta1.VolumeSnapshot{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "snap-1"},
+		Status:     &v1beta1.VolumeSnapshotStatus{BoundVolumeSnapshotContentName: &name},
+	}
+	diags := ValidateVolumeSnapshotBinding(snap, func(string) (bool, error) { return false, fmt.Errorf("cluster unreachable") })
+	if len(diags) != 1 || diags[0].Rule != "bound-content-exists" {
+		t.Fatalf("expected a single bound-content-exists diagnostic, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotClassDriverRules(t *testing.T) {
+	empty := &v1beta1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "class-1"}}
+	diags := ValidateVolumeSnapshotClass(empty, nil)
+	if len(diags) != 1 || diags[0].Rule != "class-driver-set" {
+		t.Fatalf("expected a single class-driver-set diagnostic, got %+v", diags)
+	}
+
+	unknown := &v1beta1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "class-1"}, Driver: "driver-1"}
+	diags = ValidateVolumeSnapshotClass(unknown, map[string]bool{"driver-2": true})
+	if len(diags) != 1 || diags[0].Rule != "class-driver-installed" {
+		t.Fatalf("expected a single class-driver-installed diagnostic, got %+v", diags)
+	}
+
+	known := &v1beta1.VolumeSnapshotClass{ObjectMeta: metav1.ObjectMeta{Name: "class-1"}, Driver: "driver-1"}
+	if diags := ValidateVolumeSnapshotClass(known, map[string]bool{"driver-1": true}); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics for a known driver, got %+v", diags)
+	}
+
+	if diags := ValidateVolumeSnapshotClass(known, nil); len(diags) != 0 {
+		t.Fatalf("expected the installed-driver check to be skipped when knownDrivers is nil, got %+v", diags)
+	}
+}
+
+func TestValidateVolumeSnapshotClassParameterAllowlist(t *testing.T) {
+	recognized := &v1beta1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-1"},
+		Driver:     "driver-1",
+		Parameters: map[string]string{"csi.storage.k8s.io/snapshotter-secret-name": "secret-1"},
+	}
+	if diags := ValidateVolumeSnapshotClass(recognized, nil); len(diags) != 0 {
+		t.Fatalf("expected no diagnostics for a recognized reserved key, got %+v", diags)
+	}
+
+	typo := &v1beta1.VolumeSnapshotClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "class-1"},
+		Driver:     "driver-1",
+		Parameters: map[string]string{"csi.storage.k8s.io/snapshoter-secret-name": "secret-1"},
+	}
+	diags := ValidateVolumeSnapshotClass(typo, nil)
+	if len(diags) != 1 || diags[0].Rule != "class-parameter-allowlist" {
+		t.Fatalf("expected a single class-parameter-allowlist diagnostic, got %+v", diags)
+	}
+}